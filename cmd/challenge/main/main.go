@@ -5,14 +5,30 @@ import (
 	"challenge-service/internal/domain/challenge/commands"
 	"challenge-service/internal/domain/challenge/delievery/http"
 	"challenge-service/internal/domain/challenge/delievery/http/handlers"
+	"challenge-service/internal/domain/challenge/events"
 	"challenge-service/internal/domain/challenge/queries"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/database/migrations"
 	"challenge-service/internal/infrastructure/database/postgres"
-	"challenge-service/internal/infrastructure/lib/fabric"
+	"challenge-service/internal/infrastructure/health"
+	"challenge-service/internal/infrastructure/lib/auth"
+	"challenge-service/internal/infrastructure/lib/imaging"
+	logger "challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"challenge-service/internal/infrastructure/lib/validation"
+	"challenge-service/internal/infrastructure/lifecycle"
+	"challenge-service/internal/infrastructure/metrics"
+	"challenge-service/internal/infrastructure/notifications"
+	"challenge-service/internal/infrastructure/outbox"
 	"challenge-service/internal/infrastructure/repository"
+	"challenge-service/internal/infrastructure/scheduler"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
 	"gorm.io/gorm"
 	"log/slog"
 	"os"
+	"slices"
 )
 
 const (
@@ -33,41 +49,222 @@ func main() {
 	}
 	dbClient = client.(*gorm.DB)
 
-	defer func(pgConnect postgres.PostgresConnectable, i interface{}) {
-		err := pgConnect.CloseConnection(i)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(context.Background(), log, dbClient, os.Args[2:])
+		if closeErr := pgConnect.CloseConnection(dbClient); closeErr != nil {
+			log.Error("Failed to close database connection", logger.Err(closeErr))
+		}
 		if err != nil {
-			panic(err)
+			log.Error("Migration failed", logger.Err(err))
+			os.Exit(1)
 		}
-	}(pgConnect, dbClient)
+		return
+	}
+	if err := checkMigrations(cfg, log, dbClient); err != nil {
+		panic(err)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		panic(err)
+	}
+	if err := dbClient.Use(tracing.GormPlugin{}); err != nil {
+		panic(err)
+	}
 	challengeRepo := repository.NewChallengeRepository(cfg, log, dbClient)
-	handlerFabric := fabric.NewHandlerFabric()
-	initializeHandlers(handlerFabric, log, cfg, challengeRepo)
-	challengeHandlers := handlers.NewChallengesHandlers(cfg, log, handlerFabric, challengeRepo)
-	httpServer := http.NewHTTPServer(cfg, log, challengeHandlers)
-	httpServer.Run()
+	var serviceMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		serviceMetrics = metrics.New()
+		sqlDB, err := dbClient.DB()
+		if err != nil {
+			panic(err)
+		}
+		serviceMetrics.RegisterDB(sqlDB, cfg.DatabaseName, cfg.Metrics.ScrapeTimeout)
+		challengeRepo = repository.NewInstrumentedRepository(challengeRepo, serviceMetrics)
+	}
+	notificationDispatcher, err := notifications.NewDispatcher(cfg, log, nil)
+	if err != nil {
+		panic(err)
+	}
+	eventBus := cqrs.NewEventBus(log)
+	// уведомления рассылаются из outbox после коммита, без него - подписчиком шины в процессе
+	if !cfg.Outbox.Enabled {
+		events.SubscribeNotifications(eventBus, notificationDispatcher)
+	}
+	commandBus := cqrs.NewBus()
+	commandBus.Use(cqrs.Tracing(), cqrs.Logging(log))
+	if serviceMetrics != nil {
+		serviceMetrics.SubscribeEvents(eventBus)
+		commandBus.Use(cqrs.Metrics(serviceMetrics))
+	}
+	commandBus.Use(cqrs.Validation(validation.New()), cqrs.Transaction(repository.NewTransactor(dbClient)))
+	if err := initializeHandlers(commandBus, log, cfg, challengeRepo, eventBus); err != nil {
+		panic(err)
+	}
+	imageStorage, err := storage.NewStorage(cfg, log)
+	if err != nil {
+		panic(err)
+	}
+	challengeHandlers := handlers.NewChallengesHandlers(cfg, log, commandBus, challengeRepo, imageStorage,
+		imaging.NewProcessor(cfg.Images))
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		panic(err)
+	}
+	var devPrincipal *auth.Principal
+	if !cfg.Auth.Enabled {
+		if devPrincipal, err = auth.DevPrincipal(cfg.Auth); err != nil {
+			panic(err)
+		}
+		log.Warn("authentication is disabled, requests are executed as the dev principal",
+			slog.Int64("user_id", devPrincipal.UserID))
+	}
+	healthChecker, err := newHealthChecker(cfg, dbClient, imageStorage, notificationDispatcher)
+	if err != nil {
+		panic(err)
+	}
+	httpServer := http.NewHTTPServer(cfg, log, challengeHandlers, handlers.NewHealthHandlers(healthChecker),
+		verifier, devPrincipal, imageStorage, serviceMetrics)
+	challengeScheduler := scheduler.NewChallengeScheduler(cfg, log, commandBus, challengeRepo, notificationDispatcher)
+	trashPurger := scheduler.NewTrashPurger(cfg, log, challengeRepo, imageStorage)
+
+	// компоненты останавливаются в обратном порядке: сначала HTTP сервер дожидается текущих запросов,
+	// затем останавливаются фоновые воркеры, отправляются уведомления из очереди, закрывается пул БД
+	// и последними отправляются накопленные спаны
+	manager := lifecycle.NewManager(log, cfg.ShutdownTimeout)
+	manager.Add(lifecycle.Hook{ComponentName: "tracing", OnStop: shutdownTracing})
+	manager.Add(lifecycle.Hook{
+		ComponentName: "database",
+		OnStop: func(ctx context.Context) error {
+			return pgConnect.CloseConnection(dbClient)
+		},
+	})
+	manager.Add(lifecycle.Hook{
+		ComponentName: "notifications",
+		OnStart: func(ctx context.Context) error {
+			notificationDispatcher.Start()
+			return nil
+		},
+		OnStop: notificationDispatcher.Stop,
+	})
+	if cfg.Outbox.Enabled {
+		sinks, err := outbox.NewSinks(cfg.Outbox, log)
+		if err != nil {
+			panic(err)
+		}
+		sinks = append(sinks, notifications.NewOutboxSink(notificationDispatcher))
+		outboxRelay := outbox.NewRelay(cfg.Outbox, log, dbClient, sinks...)
+		manager.Add(lifecycle.Hook{
+			ComponentName: "outbox",
+			OnStart: func(ctx context.Context) error {
+				outboxRelay.Start(ctx)
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				outboxRelay.Stop()
+				return nil
+			},
+		})
+	}
+	manager.Add(lifecycle.Hook{
+		ComponentName: "scheduler",
+		OnStart: func(ctx context.Context) error {
+			challengeScheduler.Start(ctx)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			challengeScheduler.Stop()
+			return nil
+		},
+	})
+	manager.Add(lifecycle.Hook{
+		ComponentName: "trash-purger",
+		OnStart: func(ctx context.Context) error {
+			trashPurger.Start(ctx)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			trashPurger.Stop()
+			return nil
+		},
+	})
+	manager.Add(httpServer)
+
+	if err := manager.Run(context.Background()); err != nil {
+		log.Error("Service stopped with error", logger.Err(err))
+		os.Exit(1)
+	}
+	log.Info("Service stopped")
 }
 
+// newHealthChecker собирает проверки /readyz, зависимости из health.optional считаются необязательными
+func newHealthChecker(cfg *config.Config, db *gorm.DB, imageStorage storage.Storage,
+	notifier *notifications.Dispatcher) (*health.Checker, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	checks := []health.Check{
+		{Name: "database", Func: sqlDB.PingContext},
+		{Name: "storage", Func: imageStorage.Ping},
+		{Name: "notifications", Func: notifier.Ping},
+	}
+	for i := range checks {
+		checks[i].Optional = slices.Contains(cfg.Health.Optional, checks[i].Name)
+	}
+	return health.NewChecker(cfg.Health.Timeout, checks...), nil
+}
+
+// checkMigrations применяет миграции при старте, если включен migrateOnStart, иначе предупреждает о непримененных
+func checkMigrations(cfg *config.Config, log *slog.Logger, db *gorm.DB) error {
+	migrator, err := migrations.NewMigrator(db, log)
+	if err != nil {
+		return err
+	}
+	if cfg.MigrateOnStart {
+		_, err := migrator.Up(context.Background())
+		return err
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		log.Warn("Database schema is outdated, run `migrate up`", slog.Int("pending", pending))
+	}
+	return nil
+}
+
+// initializeHandlers регистрирует обработчики команд и запросов и проверяет, что у каждой
+// команды и запроса есть ровно один обработчик
 func initializeHandlers(
-	handlerFabric *fabric.HandlerFabric,
+	bus *cqrs.Bus,
 	log *slog.Logger,
 	config *config.Config,
-	companyRepo repository_interface.ChallengeRepositoryInterface) {
-	createChallengeHandler := commands.NewCreateChallengeHandler(log, config, companyRepo)
-	updateChallengeHandler := commands.NewUpdateChallengeHandler(log, config, companyRepo)
-	deleteChallengeHandler := commands.NewDeleteChallengeHandler(log, config, companyRepo)
-	findAllHandler := queries.NewFindAllQueryHandler(log, config, companyRepo)
-	findByParamsHandler := queries.NewFindByParamsQueryHandler(log, config, companyRepo)
-	getAllChallengesFromTeamHandler := queries.NewGetAllChallengesFromTeamQueryHandler(log, config, companyRepo)
-	getAllChallengesFromUserHandler := queries.NewGetAllChallengesFromUserQueryHandler(log, config, companyRepo)
-
-	handlerFabric.RegisterCommandHandler(commands.NewEmptyCreateChallengeCommand(), createChallengeHandler)
-	handlerFabric.RegisterCommandHandler(commands.NewEmptyUpdateChallengeCommand(), updateChallengeHandler)
-	handlerFabric.RegisterCommandHandler(commands.NewEmptyDeleteChallengeCommand(), deleteChallengeHandler)
-	handlerFabric.RegisterQueryHandler(queries.NewFindAllQuery(), findAllHandler)
-	handlerFabric.RegisterQueryHandler(queries.NewEmptyFindByParamsQuery(), findByParamsHandler)
-	handlerFabric.RegisterQueryHandler(queries.NewEmptyGetAllChallengesFromTeamQuery(), getAllChallengesFromTeamHandler)
-	handlerFabric.RegisterQueryHandler(queries.NewEmptyGetAllChallengesFromUserQuery(), getAllChallengesFromUserHandler)
+	companyRepo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) error {
+	cqrs.RegisterCommand(bus, commands.NewCreateChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewUpdateChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewDeleteChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewRestoreChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewRecordProgressHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterCommand(bus, commands.NewRegisterParticipantHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewPublishChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewCancelChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewArchiveChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewCloseChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterCommand(bus, commands.NewStartChallengeHandler(log, config, companyRepo, publisher).Handle)
+	cqrs.RegisterQuery(bus, queries.NewFindAllQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewFindByParamsQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewGetChallengeQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewListTrashQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewGetAllChallengesFromTeamQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewGetAllChallengesFromUserQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewGetLeaderboardQueryHandler(log, config, companyRepo).Handle)
+	cqrs.RegisterQuery(bus, queries.NewSearchChallengesQueryHandler(log, config, companyRepo).Handle)
 
+	bus.DeclareCommands(commands.All()...)
+	bus.DeclareQueries(queries.All()...)
+	return bus.Validate()
 }
 
 func setupLogger(env string) *slog.Logger {
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"challenge-service/internal/infrastructure/database/migrations"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: main migrate up | down [steps] | status"
+
+// runMigrate выполняет подкоманду migrate: up применяет все миграции, down откатывает steps последних
+// (по умолчанию одну), status выводит состояние каждой миграции
+func runMigrate(ctx context.Context, log *slog.Logger, db *gorm.DB, args []string) error {
+	migrator, err := migrations.NewMigrator(db, log)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info("migrations applied", slog.Int("count", count))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid steps %q: %s", args[1], migrateUsage)
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		log.Info("migrations rolled back", slog.Int("count", count))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q: %s", args[0], migrateUsage)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type Config struct {
@@ -15,8 +16,79 @@ type Config struct {
 	DatabaseName     string `yaml:"databaseName" env-default:"postgres"`
 	DatabasePassword string `yaml:"databasePassword" env-default:"postgres"`
 	SecretKey        string `yaml:"secretKey" env-default:"secret-key"`
-	TgMessageURL     string `yaml:"tgMessageURL" env-default:"https://t.me/%s"`
+	TgMessageURL     string `yaml:"tgMessageURL"` // пустой адрес отключает уведомления
 	S3Url            string `yaml:"S3Url" env-default:"https://s3.amazonaws.com/"`
+
+	SchedulerInterval time.Duration `yaml:"schedulerInterval" env-default:"1m"`
+	MigrateOnStart    bool          `yaml:"migrateOnStart" env-default:"false"` // применять миграции при старте сервиса
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout" env-default:"30s"`  // лимит остановки каждого компонента
+
+	HTTP          HTTPConfig          `yaml:"http"`
+	Health        HealthConfig        `yaml:"health"`
+	Metrics       MetricsConfig       `yaml:"metrics"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Auth          AuthConfig          `yaml:"auth"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Storage       StorageConfig       `yaml:"storage"`
+	Images        ImagesConfig        `yaml:"images"`
+	Outbox        OutboxConfig        `yaml:"outbox"`
+	Trash         TrashConfig         `yaml:"trash"`
+}
+
+// HTTPConfig адрес и таймауты HTTP сервера. ShutdownTimeout - время на завершение запросов при остановке
+type HTTPConfig struct {
+	Address           string        `yaml:"address" env:"HTTP_ADDRESS" env-default:":8004"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" env-default:"10s"`
+	ReadTimeout       time.Duration `yaml:"readTimeout" env-default:"60s"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout" env-default:"60s"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout" env-default:"120s"`
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout" env-default:"20s"`
+}
+
+// HealthConfig проверки /readyz. Сбой зависимостей из Optional (database, storage, notifications)
+// переводит сервис в degraded, а не в fail
+type HealthConfig struct {
+	Timeout  time.Duration `yaml:"timeout" env-default:"2s"`
+	Optional []string      `yaml:"optional" env-default:"notifications"`
+}
+
+// MetricsConfig метрики Prometheus. ScrapeTimeout ограничивает запросы доменных показателей к БД
+type MetricsConfig struct {
+	Enabled       bool          `yaml:"enabled" env-default:"true"`
+	Path          string        `yaml:"path" env-default:"/metrics"`
+	ScrapeTimeout time.Duration `yaml:"scrapeTimeout" env-default:"2s"`
+}
+
+// TracingConfig трассировка OpenTelemetry. Exporter: none, stdout или otlp (OTLP/HTTP на OTLPEndpoint)
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	ServiceName  string  `yaml:"serviceName" env-default:"challenge-service"`
+	OTLPEndpoint string  `yaml:"otlpEndpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" env-default:"localhost:4318"`
+	Insecure     bool    `yaml:"insecure" env-default:"true"`
+	SampleRatio  float64 `yaml:"sampleRatio" env-default:"1"`
+}
+
+// AuthConfig настройки проверки JWT. HS256 проверяется ключом SecretKey,
+// RS256/ES256 - ключами из JWKS файла или URL. TeamsClaim - claim со списком команд пользователя,
+// от имени которых он может регистрироваться и отмечать прогресс. Если Enabled выключен,
+// все запросы выполняются от имени DevPrincipal, только для разработки
+type AuthConfig struct {
+	Enabled             bool          `yaml:"enabled" env-default:"true"`
+	Algorithms          []string      `yaml:"algorithms" env-default:"HS256"`
+	JWKSFile            string        `yaml:"jwksFile"`
+	JWKSURL             string        `yaml:"jwksURL"`
+	JWKSRefreshInterval time.Duration `yaml:"jwksRefreshInterval" env-default:"10m"`
+	Issuer              string        `yaml:"issuer"`
+	Audience            string        `yaml:"audience"`
+	TeamsClaim          string        `yaml:"teamsClaim" env-default:"team_ids"`
+	DevPrincipal        DevPrincipal  `yaml:"devPrincipal"`
+}
+
+// DevPrincipal пользователь запросов при выключенной проверке токенов
+type DevPrincipal struct {
+	UserID  int64    `yaml:"userID"`
+	Roles   []string `yaml:"roles"`
+	TeamIDs []int64  `yaml:"teamIDs"`
 }
 
 func fetchConfigPath(filename string) string {
@@ -44,3 +116,82 @@ func MustLoadConfig(filename string) *Config {
 	}
 	return config
 }
+
+// NotificationsConfig настройки рассылки уведомлений через TgMessageURL.
+// Templates переопределяет шаблоны text/template по имени события.
+type NotificationsConfig struct {
+	Workers      int               `yaml:"workers" env-default:"2"`
+	QueueSize    int               `yaml:"queueSize" env-default:"256"`
+	MaxRetries   int               `yaml:"maxRetries" env-default:"3"`
+	RetryBackoff time.Duration     `yaml:"retryBackoff" env-default:"2s"`
+	Timeout      time.Duration     `yaml:"timeout" env-default:"5s"`
+	DaysLeft     []int             `yaml:"daysLeft" env-default:"3,1"`
+	Templates    map[string]string `yaml:"templates"`
+}
+
+// StorageConfig хранилище изображений: s3, local или proxy (старый upload-сервис по адресу S3Url)
+type StorageConfig struct {
+	Backend string             `yaml:"backend" env-default:"proxy"`
+	S3      S3Config           `yaml:"s3"`
+	Local   LocalStorageConfig `yaml:"local"`
+}
+
+// S3Config настройки S3-совместимого хранилища. PublicURL задает адрес CDN для выдачи ссылок,
+// PathStyle включает адреса вида endpoint/bucket/key (нужно для MinIO)
+type S3Config struct {
+	Endpoint  string        `yaml:"endpoint" env-default:"https://s3.amazonaws.com"`
+	Region    string        `yaml:"region" env-default:"us-east-1"`
+	Bucket    string        `yaml:"bucket"`
+	Prefix    string        `yaml:"prefix"`
+	AccessKey string        `yaml:"accessKey" env:"S3_ACCESS_KEY"`
+	SecretKey string        `yaml:"secretKey" env:"S3_SECRET_KEY"`
+	PublicURL string        `yaml:"publicURL"`
+	PathStyle bool          `yaml:"pathStyle"`
+	Timeout   time.Duration `yaml:"timeout" env-default:"30s"`
+}
+
+// LocalStorageConfig хранение файлов на диске, файлы раздаются сервисом по пути /media.
+// Объекты с ключами из PublicPrefixes публичны, как и в S3, остальные отдаются только
+// по подписанным ссылкам. ServeUnsigned разрешает отдавать любые файлы без подписи, только для разработки
+type LocalStorageConfig struct {
+	Dir            string   `yaml:"dir" env-default:"./media"`
+	PublicURL      string   `yaml:"publicURL" env-default:"http://localhost:8004/media"`
+	PublicPrefixes []string `yaml:"publicPrefixes" env-default:"challenges/"`
+	ServeUnsigned  bool     `yaml:"serveUnsigned" env-default:"false"`
+}
+
+// ImagesConfig ограничения на загружаемые изображения. MaxDimension - наибольшая сторона
+// сохраняемого исходника, MaxPixels защищает от распаковки огромных изображений
+type ImagesConfig struct {
+	MaxSize      int64    `yaml:"maxSize" env-default:"10485760"`
+	MaxPixels    int      `yaml:"maxPixels" env-default:"40000000"`
+	MaxDimension int      `yaml:"maxDimension" env-default:"2560"`
+	AllowedTypes []string `yaml:"allowedTypes" env-default:"image/jpeg,image/png,image/webp"`
+	JPEGQuality  int      `yaml:"jpegQuality" env-default:"85"`
+}
+
+// OutboxConfig доставка событий из таблицы outbox. Sinks - список приемников (log, webhook),
+// уведомления рассылаются отдельным приемником, пока outbox включен. После MaxAttempts неудачных попыток
+// сообщение переводится в dead letter. ClaimTimeout - на сколько экземпляр забирает пачку себе:
+// если он не успел записать результат, пачку доставит другой экземпляр
+type OutboxConfig struct {
+	Enabled        bool          `yaml:"enabled" env-default:"true"`
+	PollInterval   time.Duration `yaml:"pollInterval" env-default:"1s"`
+	BatchSize      int           `yaml:"batchSize" env-default:"50"`
+	ClaimTimeout   time.Duration `yaml:"claimTimeout" env-default:"10m"`
+	MaxAttempts    int           `yaml:"maxAttempts" env-default:"10"`
+	RetryBackoff   time.Duration `yaml:"retryBackoff" env-default:"5s"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff" env-default:"10m"`
+	Sinks          []string      `yaml:"sinks" env-default:"log"`
+	WebhookURL     string        `yaml:"webhookURL"`
+	WebhookSecret  string        `yaml:"webhookSecret" env:"OUTBOX_WEBHOOK_SECRET"`
+	WebhookTimeout time.Duration `yaml:"webhookTimeout" env-default:"10s"`
+}
+
+// TrashConfig корзина удаленных вызовов. Через Retention после удаления вызов вместе с участниками
+// удаляется окончательно, очистка запускается раз в PurgeInterval и удаляет не больше PurgeBatchSize вызовов
+type TrashConfig struct {
+	Retention      time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval  time.Duration `yaml:"purgeInterval" env-default:"1h"`
+	PurgeBatchSize int           `yaml:"purgeBatchSize" env-default:"100"`
+}
@@ -21,33 +21,147 @@ const docTemplate = `{
     "paths": {
         "/challenges": {
             "get": {
-                "description": "Fetches a list of all challenges",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetches a page of challenges filtered and sorted by the query parameters",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Challenges"
                 ],
-                "summary": "Retrieve all challenges",
+                "summary": "Retrieve challenges",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "start_date",
+                            "end_date",
+                            "name",
+                            "participants"
+                        ],
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "family",
+                            "personal",
+                            "group"
+                        ],
+                        "type": "string",
+                        "description": "Challenge type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Team challenges only",
+                        "name": "is_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Finished challenges only",
+                        "name": "is_finished",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Creator ID",
+                        "name": "creator_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date from (RFC 3339)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date to (RFC 3339)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date from (RFC 3339)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date to (RFC 3339)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.AuthenticationChallenge"
-                            }
+                            "$ref": "#/definitions/repository_interface.ChallengePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Creates a new challenge with the provided data",
                 "consumes": [
                     "multipart/form-data"
@@ -61,20 +175,31 @@ const docTemplate = `{
                 "summary": "Create a new challenge",
                 "parameters": [
                     {
-                        "description": "Challenge Data",
+                        "type": "string",
+                        "description": "Challenge Data (JSON entity.AuthenticationChallenge)",
                         "name": "challenge",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.AuthenticationChallenge"
-                        }
+                        "in": "formData",
+                        "required": true
                     },
                     {
                         "type": "file",
-                        "description": "Image File",
+                        "description": "Image File (JPEG, PNG or WebP)",
                         "name": "image",
                         "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Icon File (JPEG, PNG or WebP)",
+                        "name": "icon",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -87,13 +212,37 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -101,7 +250,12 @@ const docTemplate = `{
         },
         "/challenges/close/{challenge_id}": {
             "post": {
-                "description": "This method closes challenge and send message to winner",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves an active challenge to the finished state",
                 "produces": [
                     "application/json"
                 ],
@@ -116,75 +270,165 @@ const docTemplate = `{
                         "name": "challenge_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.AuthenticationChallenge"
-                            }
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/challenges/team/register/{team_id}": {
-            "post": {
-                "description": "Register team on challenge",
+        "/challenges/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search over challenge names and descriptions (Russian and English).\nResults are ranked by relevance, matches in highlights are wrapped in \u003cmark\u003e",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Challenges"
                 ],
-                "summary": "Register team on challenge",
+                "summary": "Search challenges",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Team ID",
-                        "name": "team_id",
-                        "in": "path",
+                        "description": "Search query, supports quotes, OR and -word",
+                        "name": "q",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "family",
+                            "personal",
+                            "group"
+                        ],
+                        "type": "string",
+                        "description": "Challenge type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Team challenges only",
+                        "name": "is_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.AuthenticationParticipant"
-                            }
+                            "$ref": "#/definitions/repository_interface.ChallengeSearchPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/challenges/team/{team_id}": {
-            "get": {
-                "description": "Retrieves all challenges associated with a specific team",
+        "/challenges/team/register/{team_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register team on challenge. Only scheduled and active team challenges accept registrations\nThe caller must be a member of the team (team_ids token claim)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Challenges"
                 ],
-                "summary": "Get challenges for a team",
+                "summary": "Register team on challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -192,161 +436,1153 @@ const docTemplate = `{
                         "name": "team_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Challenge to register on",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.AuthenticationChallenge"
-                            }
+                            "$ref": "#/definitions/entity.AuthenticationParticipant"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
-                    }
-                }
-            }
-        },
-        "/challenges/user/register": {
-            "post": {
-                "description": "Register user on challenge",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Challenges"
-                ],
-                "summary": "Register user on challenge",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.AuthenticationParticipant"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
-                    }
-                }
-            }
-        },
-        "/challenges/user/{user_id}": {
-            "get": {
-                "description": "Retrieves all challenges associated with a specific user",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Challenges"
-                ],
-                "summary": "Get challenges for a user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.AuthenticationChallenge"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/challenges/{id}": {
-            "put": {
-                "description": "Updates the details of an existing challenge",
-                "consumes": [
-                    "multipart/form-data"
+        "/challenges/team/{team_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retrieves all challenges associated with a specific team",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Challenges"
                 ],
-                "summary": "Update an existing challenge",
+                "summary": "Get challenges for a team",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Challenge ID",
-                        "name": "id",
+                        "description": "Team ID",
+                        "name": "team_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Challenge Data",
-                        "name": "challenge",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/entity.AuthenticationChallenge"
-                        }
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "start_date",
+                            "end_date",
+                            "name",
+                            "participants"
+                        ],
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "family",
+                            "personal",
+                            "group"
+                        ],
+                        "type": "string",
+                        "description": "Challenge type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Team challenges only",
+                        "name": "is_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Finished challenges only",
+                        "name": "is_finished",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Creator ID",
+                        "name": "creator_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date from (RFC 3339)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date to (RFC 3339)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date from (RFC 3339)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date to (RFC 3339)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/repository_interface.ChallengePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns challenges of the current user that are in the trash, most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Get deleted challenges",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.AuthenticationChallenge"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/user/register": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register user on challenge. Only scheduled and active challenges accept registrations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Register user on challenge",
+                "parameters": [
+                    {
+                        "description": "Challenge to register on",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationParticipant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/user/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves all challenges associated with a specific user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Get challenges for a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "start_date",
+                            "end_date",
+                            "name",
+                            "participants"
+                        ],
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "family",
+                            "personal",
+                            "group"
+                        ],
+                        "type": "string",
+                        "description": "Challenge type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Team challenges only",
+                        "name": "is_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Finished challenges only",
+                        "name": "is_finished",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Creator ID",
+                        "name": "creator_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date from (RFC 3339)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date to (RFC 3339)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date from (RFC 3339)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date to (RFC 3339)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/repository_interface.ChallengePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a challenge with its ETag. Responds 304 if If-None-Match contains the current ETag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Get a challenge",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached challenge",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Challenge version"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates only the fields present in the request. If-Match with the ETag of the challenge\nis required: the update is rejected with 412 if the challenge was changed since it was read\nand with 428 without the header. If-Match: * updates any version.\nThe icon and image are replaced only by uploading a file, which also regenerates their\nresized variants. An icon or image URL in the JSON data must equal the current one,\nany other URL is rejected with 422",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Update an existing challenge",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the challenge version the update is based on",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated Challenge Data (JSON commands.UpdateChallengeCommand)",
+                        "name": "challenge",
+                        "in": "formData"
                     },
                     {
                         "type": "file",
-                        "description": "New Image File",
+                        "description": "New Image File (JPEG, PNG or WebP)",
                         "name": "image",
                         "in": "formData"
                     },
                     {
-                        "type": "file",
-                        "description": "New Icon File",
-                        "name": "icon",
-                        "in": "formData"
+                        "type": "file",
+                        "description": "New Icon File (JPEG, PNG or WebP)",
+                        "name": "icon",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Challenge version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a challenge to the trash. It can be restored until the trash retention period expires",
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Delete a challenge",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DeleteChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Archives a finished or cancelled challenge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Archive challenge",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels a draft, scheduled or active challenge",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Cancel challenge",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ranks participants by accumulated progress. Team challenges rank teams, personal challenges rank users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Get challenge leaderboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 10, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of top entries to skip, next_offset from the previous page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Caller's team for team challenges, the caller must be a member of it",
+                        "name": "team_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Leaderboard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}/progress": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Appends a progress check-in of the current participant to the challenge\nvalue must be in (0, 100000]. timestamp defaults to now, it cannot be in the future\nor more than 48 hours in the past and must be within the challenge dates.\nWith team_id the check-in goes to the team, the caller must be a member of it (team_ids token claim)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Record progress check-in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Check-in",
+                        "name": "check_in",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecordProgressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationParticipant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/challenges/{id}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a draft challenge to scheduled, or to active if its start date has passed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Challenges"
+                ],
+                "summary": "Publish challenge",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {}
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes a challenge by its ID",
+            }
+        },
+        "/challenges/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a challenge from the trash",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Challenges"
                 ],
-                "summary": "Delete a challenge",
+                "summary": "Restore challenge",
                 "parameters": [
                     {
                         "type": "integer",
@@ -354,25 +1590,81 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language of type_label (ru, en), ru by default",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.DeleteChallengeResponse"
+                            "$ref": "#/definitions/entity.AuthenticationChallenge"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Challenge version"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Responds 200 while the process is running, dependencies are not checked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
                         }
                     }
                 }
@@ -397,15 +1689,52 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks the database, the storage backend and the notification service.\nResponds 200 when all dependencies are ok or only optional ones fail (status degraded), 503 otherwise",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
         "entity.AuthenticationChallenge": {
             "type": "object",
             "properties": {
+                "co_organizer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "creator_id": {
                     "type": "integer"
                 },
+                "deleted_at": {
+                    "description": "DeletedAt время перемещения в корзину. Удаленные вызовы не попадают в выборки репозитория",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -415,12 +1744,18 @@ const docTemplate = `{
                 "icon": {
                     "type": "string"
                 },
+                "icon_variants": {
+                    "$ref": "#/definitions/entity.ImageVariants"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "image": {
                     "type": "string"
                 },
+                "image_variants": {
+                    "$ref": "#/definitions/entity.ImageVariants"
+                },
                 "is_finished": {
                     "type": "boolean"
                 },
@@ -430,12 +1765,26 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "participant_count": {
+                    "description": "ParticipantCount заполняется только в выдаче списков",
+                    "type": "integer"
+                },
                 "start_date": {
                     "type": "string"
                 },
+                "status": {
+                    "$ref": "#/definitions/entity.ChallengeStatus"
+                },
                 "type": {
-                    "description": "семейный, личный, общий(групповой)",
+                    "$ref": "#/definitions/entity.ChallengeKind"
+                },
+                "type_label": {
+                    "description": "TypeLabel подпись вида на языке запроса, заполняется транспортом через Localize",
                     "type": "string"
+                },
+                "version": {
+                    "description": "растет при каждом изменении, из нее строится ETag",
+                    "type": "integer"
                 }
             }
         },
@@ -458,7 +1807,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "progress": {
-                    "type": "string"
+                    "$ref": "#/definitions/entity.ParticipantProgress"
                 },
                 "status": {
                     "type": "string"
@@ -468,6 +1817,140 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ChallengeKind": {
+            "type": "string",
+            "enum": [
+                "family",
+                "personal",
+                "group"
+            ],
+            "x-enum-varnames": [
+                "ChallengeKindFamily",
+                "ChallengeKindPersonal",
+                "ChallengeKindGroup"
+            ]
+        },
+        "entity.ChallengeStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "scheduled",
+                "active",
+                "finished",
+                "archived",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "ChallengeStatusDraft",
+                "ChallengeStatusScheduled",
+                "ChallengeStatusActive",
+                "ChallengeStatusFinished",
+                "ChallengeStatusArchived",
+                "ChallengeStatusCancelled"
+            ]
+        },
+        "entity.CheckIn": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.ImageVariants": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "entity.Leaderboard": {
+            "type": "object",
+            "properties": {
+                "challenge_id": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LeaderboardEntry"
+                    }
+                },
+                "is_team": {
+                    "type": "boolean"
+                },
+                "me": {
+                    "$ref": "#/definitions/entity.LeaderboardEntry"
+                },
+                "next_offset": {
+                    "description": "offset следующей страницы, если она есть",
+                    "type": "integer"
+                },
+                "participants": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "days_done": {
+                    "type": "integer"
+                },
+                "last_check_in": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ParticipantProgress": {
+            "type": "object",
+            "properties": {
+                "check_ins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CheckIn"
+                    }
+                },
+                "days_done": {
+                    "type": "integer"
+                },
+                "days_total": {
+                    "type": "integer"
+                },
+                "last_check_in": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
         "handlers.DeleteChallengeResponse": {
             "type": "object",
             "properties": {
@@ -476,7 +1959,7 @@ const docTemplate = `{
                 }
             }
         },
-        "handlers.ErrorResponse": {
+        "handlers.PingResponse": {
             "type": "object",
             "properties": {
                 "message": {
@@ -484,14 +1967,191 @@ const docTemplate = `{
                 }
             }
         },
-        "handlers.PingResponse": {
+        "handlers.RecordProgressRequest": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "challenge_id"
+            ],
+            "properties": {
+                "challenge_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "$ref": "#/definitions/health.Status"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/health.Status"
+                }
+            }
+        },
+        "health.Status": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "degraded",
+                "fail"
+            ],
+            "x-enum-varnames": [
+                "StatusOK",
+                "StatusDegraded",
+                "StatusFail"
+            ]
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "challenge_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "challenge not found"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/challenges/42"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "repository_interface.ChallengePage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AuthenticationChallenge"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository_interface.ChallengeSearchPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository_interface.ChallengeSearchResult"
+                    }
+                }
+            }
+        },
+        "repository_interface.ChallengeSearchResult": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "$ref": "#/definitions/entity.AuthenticationChallenge"
+                },
+                "description_highlight": {
+                    "type": "string"
+                },
+                "name_highlight": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                }
+            }
+        },
+        "validation.FieldError": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
                 "message": {
                     "type": "string"
+                },
+                "param": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
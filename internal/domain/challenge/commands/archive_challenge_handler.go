@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type ArchiveChallengeHandler struct {
+	cqrs.CommandHandler[*ArchiveChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
+}
+
+func NewArchiveChallengeHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *ArchiveChallengeHandler {
+	return &ArchiveChallengeHandler{
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (h *ArchiveChallengeHandler) Handle(ctx context.Context, command *ArchiveChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	result, err := transitionChallenge(ctx, h.repo, h.publisher, command.ChallengeID, func(challenge *entity.AuthenticationChallenge) error {
+		return challenge.Archive()
+	}, challengeUpdated)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type CancelChallengeHandler struct {
+	cqrs.CommandHandler[*CancelChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
+}
+
+func NewCancelChallengeHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *CancelChallengeHandler {
+	return &CancelChallengeHandler{
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (h *CancelChallengeHandler) Handle(ctx context.Context, command *CancelChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	result, err := transitionChallenge(ctx, h.repo, h.publisher, command.ChallengeID, func(challenge *entity.AuthenticationChallenge) error {
+		return challenge.Cancel()
+	}, challengeUpdated)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
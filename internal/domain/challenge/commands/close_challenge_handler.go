@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type CloseChallengeHandler struct {
+	cqrs.CommandHandler[*CloseChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
+}
+
+func NewCloseChallengeHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *CloseChallengeHandler {
+	return &CloseChallengeHandler{
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (h *CloseChallengeHandler) Handle(ctx context.Context, command *CloseChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	return transitionChallenge(ctx, h.repo, h.publisher, command.ChallengeID, func(challenge *entity.AuthenticationChallenge) error {
+		return challenge.Finish()
+	}, closeParticipants)
+}
+
+// closeParticipants фиксирует итог для каждого активного участника закрытого вызова
+func closeParticipants(ctx context.Context, repo repository_interface.ChallengeRepositoryInterface,
+	challenge *entity.AuthenticationChallenge) ([]cqrs.Event, error) {
+	participants, err := repo.GetParticipants(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if participant.Status != entity.ParticipantStatusActive {
+			continue
+		}
+		outcome := participant.Outcome(*challenge)
+		if err := repo.UpdateParticipantStatus(ctx, participant.ID, outcome); err != nil {
+			return nil, err
+		}
+		participant.Status = outcome
+	}
+	return []cqrs.Event{events.NewChallengeClosed(challenge, participants)}, nil
+}
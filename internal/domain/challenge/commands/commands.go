@@ -1,30 +1,54 @@
 package commands
 
 import (
+	"challenge-service/internal/domain/challenge/entity"
 	"challenge-service/internal/infrastructure/cqrs"
 	"time"
 )
 
+// All команды сервиса, для каждой при старте должен быть зарегистрирован ровно один обработчик
+func All() []cqrs.Command {
+	return []cqrs.Command{
+		NewEmptyCreateChallengeCommand(),
+		NewEmptyUpdateChallengeCommand(),
+		NewEmptyDeleteChallengeCommand(),
+		NewEmptyRestoreChallengeCommand(),
+		NewEmptyRecordProgressCommand(),
+		NewEmptyRegisterParticipantCommand(),
+		NewEmptyPublishChallengeCommand(),
+		NewEmptyCancelChallengeCommand(),
+		NewEmptyArchiveChallengeCommand(),
+		NewEmptyCloseChallengeCommand(),
+		NewEmptyStartChallengeCommand(),
+	}
+}
+
 type CreateChallengeCommand struct {
 	cqrs.BaseCommand
-	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
-	Icon        string    `gorm:"type:varchar(255);not null" json:"icon"`
-	Image       string    `gorm:"type:varchar(255);not null" json:"image"`
-	Description string    `gorm:"type:text;not null" json:"description"`
-	StartDate   time.Time `json:"start_date"`
-	EndDate     time.Time `gorm:"type:timestamptz;not null" json:"end_date"`
-	Type        string    `gorm:"type:varchar(10);not null" json:"type"` // семейный, личный, общий(групповой)
-	IsTeam      bool      `gorm:"not null" json:"is_team"`
-	CreatorID   int64     `gorm:"not null" json:"creator_id"`
-}
-
-func NewCreateChallengeCommand(id int64, name *string, icon *string, description *string,
-	endDate *time.Time, typeChallenge *string, isTeam *bool, creatorID *int64) *CreateChallengeCommand {
+	Name        string               `gorm:"type:varchar(255);not null" json:"name" validate:"required,max=255"`
+	Icon        string               `gorm:"type:varchar(255);not null" json:"icon" validate:"required,max=255"`
+	Image       string               `gorm:"type:varchar(255);not null" json:"image" validate:"required,max=255"`
+	Description string               `gorm:"type:text;not null" json:"description" validate:"max=5000"`
+	StartDate   time.Time            `json:"start_date" validate:"required"`
+	EndDate     time.Time            `gorm:"type:timestamptz;not null" json:"end_date" validate:"required,gtfield=StartDate"`
+	Type        entity.ChallengeKind `json:"type" validate:"required,oneof=family personal group"`
+	IsTeam      bool                 `gorm:"not null" json:"is_team"`
+	CreatorID   int64                `gorm:"not null" json:"creator_id" validate:"gte=0"`
+
+	CoOrganizerIDs []int64           `json:"co_organizer_ids,omitempty" validate:"omitempty,max=20,unique,dive,gt=0"`
+	IconVariants   map[string]string `json:"-"`
+	ImageVariants  map[string]string `json:"-"`
+}
+
+func NewCreateChallengeCommand(id int64, name *string, icon *string, image *string, description *string,
+	startDate *time.Time, endDate *time.Time, typeChallenge *entity.ChallengeKind, isTeam *bool, creatorID *int64) *CreateChallengeCommand {
 	return &CreateChallengeCommand{
 		BaseCommand: cqrs.NewBaseCommand(id),
 		Name:        *name,
 		Icon:        *icon,
+		Image:       *image,
 		Description: *description,
+		StartDate:   *startDate,
 		EndDate:     *endDate,
 		Type:        *typeChallenge,
 		IsTeam:      *isTeam,
@@ -38,20 +62,28 @@ func NewEmptyCreateChallengeCommand() *CreateChallengeCommand {
 
 type UpdateChallengeCommand struct {
 	cqrs.BaseCommand
-	ChallengeID int64      `json:"challenge_id"`
-	Name        *string    `json:"name,omitempty"`
-	Icon        *string    `json:"icon,omitempty"`
-	Image       *string    `json:"image,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	StartDate   *time.Time `json:"start_date"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
-	Type        *string    `json:"type,omitempty"` // семейный, личный, общий(групповой)
-	IsTeam      *bool      `json:"is_team,omitempty"`
-	CreatorID   *int64     `json:"creator_id,omitempty"`
+	ChallengeID int64                 `json:"challenge_id" validate:"required"`
+	Name        *string               `json:"name,omitempty" validate:"omitnil,min=1,max=255"`
+	Icon        *string               `json:"icon,omitempty" validate:"omitnil,min=1,max=255"`
+	Image       *string               `json:"image,omitempty" validate:"omitnil,min=1,max=255"`
+	Description *string               `json:"description,omitempty" validate:"omitnil,max=5000"`
+	StartDate   *time.Time            `json:"start_date"`
+	EndDate     *time.Time            `json:"end_date,omitempty"`
+	Type        *entity.ChallengeKind `json:"type,omitempty" validate:"omitnil,oneof=family personal group"`
+	IsTeam      *bool                 `json:"is_team,omitempty"`
+	CreatorID   *int64                `json:"creator_id,omitempty" validate:"omitnil,gt=0"`
+
+	CoOrganizerIDs *[]int64          `json:"co_organizer_ids,omitempty" validate:"omitnil,max=20,unique,dive,gt=0"`
+	IconVariants   map[string]string `json:"-"`
+	ImageVariants  map[string]string `json:"-"`
+	// ExpectedVersions версии из If-Match, с одной из которых должна совпасть текущая.
+	// AnyVersion - If-Match: *, обновление любой версии. Без одного из них обновление отклоняется
+	ExpectedVersions []int64 `json:"-"`
+	AnyVersion       bool    `json:"-"`
 }
 
 func NewUpdateChallengeCommand(id int64, challengeID int64, name *string, icon *string, image *string, description *string,
-	endDate *time.Time, typeChallenge *string, isTeam *bool, creatorID *int64) *UpdateChallengeCommand {
+	endDate *time.Time, typeChallenge *entity.ChallengeKind, isTeam *bool, creatorID *int64) *UpdateChallengeCommand {
 	return &UpdateChallengeCommand{
 		BaseCommand: cqrs.NewBaseCommand(id),
 		ChallengeID: challengeID,
@@ -72,7 +104,7 @@ func NewEmptyUpdateChallengeCommand() *UpdateChallengeCommand {
 
 type DeleteChallengeCommand struct {
 	cqrs.BaseCommand
-	ChallengeID int64 `json:"challenge_id"`
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
 }
 
 func NewDeleteChallengeCommand(id int64, challengeID int64) *DeleteChallengeCommand {
@@ -85,3 +117,148 @@ func NewDeleteChallengeCommand(id int64, challengeID int64) *DeleteChallengeComm
 func NewEmptyDeleteChallengeCommand() *DeleteChallengeCommand {
 	return &DeleteChallengeCommand{}
 }
+
+type RestoreChallengeCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewRestoreChallengeCommand(id int64, challengeID int64) *RestoreChallengeCommand {
+	return &RestoreChallengeCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyRestoreChallengeCommand() *RestoreChallengeCommand {
+	return &RestoreChallengeCommand{}
+}
+
+type RecordProgressCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64     `json:"challenge_id" validate:"required"`
+	UserID      int64     `json:"user_id" validate:"required"`
+	TeamID      int64     `json:"team_id,omitempty" validate:"gte=0"`
+	Value       float64   `json:"value" validate:"required,gt=0,lte=100000"`
+	Unit        string    `json:"unit" validate:"max=32"`
+	Timestamp   time.Time `json:"timestamp"`
+	Note        string    `json:"note,omitempty" validate:"max=500"`
+}
+
+func NewRecordProgressCommand(id int64, challengeID int64, userID int64, teamID int64,
+	value float64, unit string, timestamp time.Time, note string) *RecordProgressCommand {
+	return &RecordProgressCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+		UserID:      userID,
+		TeamID:      teamID,
+		Value:       value,
+		Unit:        unit,
+		Timestamp:   timestamp,
+		Note:        note,
+	}
+}
+
+func NewEmptyRecordProgressCommand() *RecordProgressCommand {
+	return &RecordProgressCommand{}
+}
+
+type StartChallengeCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewStartChallengeCommand(id int64, challengeID int64) *StartChallengeCommand {
+	return &StartChallengeCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyStartChallengeCommand() *StartChallengeCommand {
+	return &StartChallengeCommand{}
+}
+
+type PublishChallengeCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewPublishChallengeCommand(id int64, challengeID int64) *PublishChallengeCommand {
+	return &PublishChallengeCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyPublishChallengeCommand() *PublishChallengeCommand {
+	return &PublishChallengeCommand{}
+}
+
+type CancelChallengeCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewCancelChallengeCommand(id int64, challengeID int64) *CancelChallengeCommand {
+	return &CancelChallengeCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyCancelChallengeCommand() *CancelChallengeCommand {
+	return &CancelChallengeCommand{}
+}
+
+type ArchiveChallengeCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewArchiveChallengeCommand(id int64, challengeID int64) *ArchiveChallengeCommand {
+	return &ArchiveChallengeCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyArchiveChallengeCommand() *ArchiveChallengeCommand {
+	return &ArchiveChallengeCommand{}
+}
+
+type CloseChallengeCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewCloseChallengeCommand(id int64, challengeID int64) *CloseChallengeCommand {
+	return &CloseChallengeCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyCloseChallengeCommand() *CloseChallengeCommand {
+	return &CloseChallengeCommand{}
+}
+
+type RegisterParticipantCommand struct {
+	cqrs.BaseCommand
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+	UserID      int64 `json:"user_id" validate:"required"`
+	TeamID      int64 `json:"team_id,omitempty" validate:"gte=0"`
+}
+
+func NewRegisterParticipantCommand(id int64, challengeID int64, userID int64, teamID int64) *RegisterParticipantCommand {
+	return &RegisterParticipantCommand{
+		BaseCommand: cqrs.NewBaseCommand(id),
+		ChallengeID: challengeID,
+		UserID:      userID,
+		TeamID:      teamID,
+	}
+}
+
+func NewEmptyRegisterParticipantCommand() *RegisterParticipantCommand {
+	return &RegisterParticipantCommand{}
+}
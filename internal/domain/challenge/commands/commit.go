@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+)
+
+// commitWithEvents выполняет изменения и сохраняет возвращенные ими события в outbox в одной транзакции,
+// после коммита публикует события во внутрипроцессную шину. Внутри транзакции команды
+// изменения присоединяются к ней, а публикация откладывается до ее коммита
+func commitWithEvents(ctx context.Context, repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher,
+	apply func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error)) error {
+	var events []cqrs.Event
+	err := repo.Transaction(ctx, func(repo repository_interface.ChallengeRepositoryInterface) error {
+		var err error
+		if events, err = apply(repo); err != nil {
+			return err
+		}
+		return repo.AppendEvents(ctx, events...)
+	})
+	if err != nil {
+		return err
+	}
+	cqrs.AfterCommit(ctx, func() {
+		publisher.Publish(ctx, events...)
+	})
+	return nil
+}
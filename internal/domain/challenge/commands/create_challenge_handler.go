@@ -3,48 +3,66 @@ package commands
 import (
 	"challenge-service/config"
 	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/policy"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
 	"context"
-	"errors"
 	"log/slog"
 )
 
 type CreateChallengeHandler struct {
-	cqrs.CommandHandler[CreateChallengeCommand]
-	log  *slog.Logger
-	cfg  *config.Config
-	repo repository_interface.ChallengeRepositoryInterface
+	cqrs.CommandHandler[*CreateChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
 }
 
 func NewCreateChallengeHandler(log *slog.Logger, cfg *config.Config,
-	repo repository_interface.ChallengeRepositoryInterface) *CreateChallengeHandler {
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *CreateChallengeHandler {
 	return &CreateChallengeHandler{
-		log:  log,
-		cfg:  cfg,
-		repo: repo,
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
 	}
 }
 
-func (c *CreateChallengeHandler) Handle(ctx context.Context, command cqrs.Command) (interface{}, error) {
-	c.log.Info("CreateChallengeHandler")
-	createChallengeCommand, ok := command.(*CreateChallengeCommand)
-	if !ok {
-		return nil, errors.New("invalid command")
+func (c *CreateChallengeHandler) Handle(ctx context.Context, command *CreateChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	creatorID, err := policy.ResolveCreator(ctx, command.CreatorID)
+	if err != nil {
+		return nil, err
 	}
 	challenge := entity.AuthenticationChallenge{
-		ID:          createChallengeCommand.AggregateID,
-		Name:        createChallengeCommand.Name,
-		Icon:        createChallengeCommand.Icon,
-		Image:       createChallengeCommand.Image,
-		Description: createChallengeCommand.Description,
-		StartDate:   createChallengeCommand.StartDate,
-		EndDate:     createChallengeCommand.EndDate,
-		Type:        createChallengeCommand.Type,
-		IsTeam:      createChallengeCommand.IsTeam,
-		CreatorID:   createChallengeCommand.CreatorID,
+		ID:          command.AggregateID,
+		Name:        command.Name,
+		Icon:        command.Icon,
+		Image:       command.Image,
+		Description: command.Description,
+		StartDate:   command.StartDate,
+		EndDate:     command.EndDate,
+		Type:        command.Type,
+		IsTeam:      command.IsTeam,
+		Status:      entity.ChallengeStatusDraft,
+		CreatorID:   creatorID,
+		Version:     1,
+
+		CoOrganizerIDs: command.CoOrganizerIDs,
+		IconVariants:   command.IconVariants,
+		ImageVariants:  command.ImageVariants,
+	}
+	if err := challenge.CheckKindRules(); err != nil {
+		return nil, err
 	}
-	result, err := c.repo.Create(challenge)
+	var result *entity.AuthenticationChallenge
+	err = commitWithEvents(ctx, c.repo, c.publisher, func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error) {
+		if result, err = repo.Create(ctx, challenge); err != nil {
+			return nil, err
+		}
+		return []cqrs.Event{events.NewChallengeCreated(result)}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -2,39 +2,50 @@ package commands
 
 import (
 	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/policy"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
 	"context"
-	"errors"
 	"log/slog"
 )
 
 type DeleteChallengeHandler struct {
-	cqrs.CommandHandler[DeleteChallengeCommand]
-	log  *slog.Logger
-	cfg  *config.Config
-	repo repository_interface.ChallengeRepositoryInterface
+	cqrs.CommandHandler[*DeleteChallengeCommand, struct{}]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
 }
 
 func NewDeleteChallengeHandler(log *slog.Logger, cfg *config.Config,
-	repo repository_interface.ChallengeRepositoryInterface) *DeleteChallengeHandler {
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *DeleteChallengeHandler {
 	return &DeleteChallengeHandler{
-		log:  log,
-		cfg:  cfg,
-		repo: repo,
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
 	}
 }
 
-func (h *DeleteChallengeHandler) Handle(ctx context.Context, command cqrs.Command) (interface{}, error) {
-	h.log.Info("DeleteChallengeHandler")
-	deleteChallengeCommand, ok := command.(*DeleteChallengeCommand)
-	if !ok {
-		return nil, errors.New("invalid command")
+func (h *DeleteChallengeHandler) Handle(ctx context.Context, command *DeleteChallengeCommand) (struct{}, error) {
+	challenge, err := h.repo.FindByID(ctx, command.ChallengeID)
+	if err != nil {
+		return struct{}{}, err
+	}
+	if err := policy.AuthorizeManage(ctx, challenge); err != nil {
+		return struct{}{}, err
 	}
 
-	err := h.repo.Delete(deleteChallengeCommand.ChallengeID)
+	err = commitWithEvents(ctx, h.repo, h.publisher, func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error) {
+		if err := repo.Delete(ctx, challenge.ID); err != nil {
+			return nil, err
+		}
+		return []cqrs.Event{events.NewChallengeDeleted(challenge.ID)}, nil
+	})
 	if err != nil {
-		return nil, err
+		return struct{}{}, err
 	}
-	return "successful deleted", nil
+	return struct{}{}, nil
 }
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type PublishChallengeHandler struct {
+	cqrs.CommandHandler[*PublishChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
+}
+
+func NewPublishChallengeHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *PublishChallengeHandler {
+	return &PublishChallengeHandler{
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (h *PublishChallengeHandler) Handle(ctx context.Context, command *PublishChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	result, err := transitionChallenge(ctx, h.repo, h.publisher, command.ChallengeID, func(challenge *entity.AuthenticationChallenge) error {
+		return challenge.Publish(time.Now())
+	}, challengeUpdated)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/policy"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type RecordProgressHandler struct {
+	cqrs.CommandHandler[*RecordProgressCommand, *entity.AuthenticationParticipant]
+	log  *slog.Logger
+	cfg  *config.Config
+	repo repository_interface.ChallengeRepositoryInterface
+}
+
+func NewRecordProgressHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface) *RecordProgressHandler {
+	return &RecordProgressHandler{
+		log:  log,
+		cfg:  cfg,
+		repo: repo,
+	}
+}
+
+func (h *RecordProgressHandler) Handle(ctx context.Context, command *RecordProgressCommand) (*entity.AuthenticationParticipant, error) {
+	if err := policy.AuthorizeActAs(ctx, command.UserID); err != nil {
+		return nil, err
+	}
+	if command.TeamID != 0 {
+		if err := policy.AuthorizeTeam(ctx, command.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	challenge, err := h.repo.FindByID(ctx, command.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := challenge.EnsureAcceptsProgress(); err != nil {
+		return nil, err
+	}
+
+	// прогресс хранится одним jsonb документом: строка участника блокируется на время
+	// чтения и записи, иначе одна из одновременных отметок потеряется
+	var result *entity.AuthenticationParticipant
+	err = h.repo.Transaction(ctx, func(repo repository_interface.ChallengeRepositoryInterface) error {
+		participant, err := repo.LockParticipant(ctx, challenge.ID, command.UserID, command.TeamID)
+		if err != nil {
+			return err
+		}
+		progress := participant.Progress
+		err = progress.AddCheckIn(entity.CheckIn{
+			Value:     command.Value,
+			Unit:      command.Unit,
+			Timestamp: command.Timestamp,
+			Note:      command.Note,
+		}, *challenge, time.Now())
+		if err != nil {
+			return err
+		}
+		result, err = repo.UpdateParticipantProgress(ctx, participant.ID, progress)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
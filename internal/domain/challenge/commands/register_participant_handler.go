@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/policy"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+type RegisterParticipantHandler struct {
+	cqrs.CommandHandler[*RegisterParticipantCommand, *entity.AuthenticationParticipant]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
+}
+
+func NewRegisterParticipantHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *RegisterParticipantHandler {
+	return &RegisterParticipantHandler{
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (h *RegisterParticipantHandler) Handle(ctx context.Context, command *RegisterParticipantCommand) (*entity.AuthenticationParticipant, error) {
+	if err := policy.AuthorizeActAs(ctx, command.UserID); err != nil {
+		return nil, err
+	}
+	if command.TeamID != 0 {
+		if err := policy.AuthorizeTeam(ctx, command.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	challenge, err := h.repo.FindByID(ctx, command.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := challenge.EnsureAcceptsRegistrations(); err != nil {
+		return nil, err
+	}
+	if challenge.IsTeam && command.TeamID == 0 {
+		return nil, entity.ErrTeamRequired
+	}
+	if !challenge.IsTeam && command.TeamID != 0 {
+		return nil, entity.ErrTeamNotAllowed
+	}
+
+	_, err = h.repo.FindParticipant(ctx, challenge.ID, command.UserID, command.TeamID)
+	if err == nil {
+		return nil, entity.ErrAlreadyRegistered
+	}
+	if !errors.Is(err, entity.ErrParticipantNotFound) {
+		return nil, err
+	}
+
+	var participant *entity.AuthenticationParticipant
+	err = commitWithEvents(ctx, h.repo, h.publisher, func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error) {
+		if challenge.IsTeam {
+			participant, err = repo.RegisterTeamOnChallenge(ctx, command.TeamID, *challenge)
+		} else {
+			participant, err = repo.RegisterUserOnChallenge(ctx, command.UserID, *challenge)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []cqrs.Event{events.NewParticipantRegistered(challenge, participant)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
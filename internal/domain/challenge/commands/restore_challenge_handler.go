@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/policy"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type RestoreChallengeHandler struct {
+	cqrs.CommandHandler[*RestoreChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
+}
+
+func NewRestoreChallengeHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *RestoreChallengeHandler {
+	return &RestoreChallengeHandler{
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (h *RestoreChallengeHandler) Handle(ctx context.Context,
+	command *RestoreChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	challenge, err := h.repo.FindDeletedByID(ctx, command.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeManage(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	var restored *entity.AuthenticationChallenge
+	err = commitWithEvents(ctx, h.repo, h.publisher, func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error) {
+		var err error
+		if restored, err = repo.Restore(ctx, *challenge); err != nil {
+			return nil, err
+		}
+		return []cqrs.Event{events.NewChallengeRestored(restored)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/policy"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+)
+
+// transitionEvents сохраняет сопутствующие переходу изменения в транзакции и возвращает его события
+type transitionEvents func(ctx context.Context, repo repository_interface.ChallengeRepositoryInterface,
+	challenge *entity.AuthenticationChallenge) ([]cqrs.Event, error)
+
+// challengeUpdated события перехода без сопутствующих изменений
+func challengeUpdated(_ context.Context, _ repository_interface.ChallengeRepositoryInterface,
+	challenge *entity.AuthenticationChallenge) ([]cqrs.Event, error) {
+	return []cqrs.Event{events.NewChallengeUpdated(challenge)}, nil
+}
+
+// transitionChallenge загружает вызов, проверяет права, применяет к нему переход жизненного цикла
+// и сохраняет новое состояние вместе с событиями перехода
+func transitionChallenge(ctx context.Context, repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher, challengeID int64,
+	transition func(challenge *entity.AuthenticationChallenge) error,
+	eventsOf transitionEvents) (*entity.AuthenticationChallenge, error) {
+	challenge, err := repo.FindByID(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeManage(ctx, challenge); err != nil {
+		return nil, err
+	}
+	if err := transition(challenge); err != nil {
+		return nil, err
+	}
+
+	var result *entity.AuthenticationChallenge
+	err = commitWithEvents(ctx, repo, publisher, func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error) {
+		var err error
+		if result, err = repo.UpdateStatus(ctx, *challenge); err != nil {
+			return nil, err
+		}
+		return eventsOf(ctx, repo, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -3,70 +3,154 @@ package commands
 import (
 	"challenge-service/config"
 	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/policy"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/lib/validation"
 	"context"
-	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 )
 
 type UpdateChallengeHandler struct {
-	cqrs.CommandHandler[UpdateChallengeCommand]
-	log  *slog.Logger
-	cfg  *config.Config
-	repo repository_interface.ChallengeRepositoryInterface
+	cqrs.CommandHandler[*UpdateChallengeCommand, *entity.AuthenticationChallenge]
+	log       *slog.Logger
+	cfg       *config.Config
+	repo      repository_interface.ChallengeRepositoryInterface
+	publisher cqrs.EventPublisher
 }
 
 func NewUpdateChallengeHandler(log *slog.Logger, cfg *config.Config,
-	repo repository_interface.ChallengeRepositoryInterface) *UpdateChallengeHandler {
+	repo repository_interface.ChallengeRepositoryInterface,
+	publisher cqrs.EventPublisher) *UpdateChallengeHandler {
 	return &UpdateChallengeHandler{
-		log:  log,
-		cfg:  cfg,
-		repo: repo,
+		log:       log,
+		cfg:       cfg,
+		repo:      repo,
+		publisher: publisher,
 	}
 }
 
-func (h *UpdateChallengeHandler) Handle(ctx context.Context, command cqrs.Command) (interface{}, error) {
-	h.log.Info("UpdateChallengeHandler")
-	updateChallengeCommand, ok := command.(*UpdateChallengeCommand)
-	if !ok {
-		return nil, errors.New("invalid command")
+func (h *UpdateChallengeHandler) Handle(ctx context.Context, command *UpdateChallengeCommand) (*entity.AuthenticationChallenge, error) {
+	// без версии параллельные обновления молча перезаписывали бы друг друга
+	if command.ExpectedVersions == nil && !command.AnyVersion {
+		return nil, entity.ErrVersionRequired
 	}
-	challenge := entity.AuthenticationChallenge{
-		ID: updateChallengeCommand.ChallengeID,
+	challenge, err := h.repo.FindByID(ctx, command.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.AuthorizeManage(ctx, challenge); err != nil {
+		return nil, err
+	}
+	if command.CreatorID != nil || command.CoOrganizerIDs != nil {
+		if err := policy.AuthorizeOwnership(ctx, challenge); err != nil {
+			return nil, err
+		}
+	}
+	if !command.AnyVersion && !slices.Contains(command.ExpectedVersions, challenge.Version) {
+		return nil, entity.ErrStaleVersion
+	}
+	if err := challenge.EnsureEditable(); err != nil {
+		return nil, err
 	}
 
-	if updateChallengeCommand.Name != nil {
-		challenge.Name = *updateChallengeCommand.Name
+	// fields поля, переданные в команде: только они попадут в UPDATE
+	var fields []string
+	if command.Name != nil {
+		challenge.Name = *command.Name
+		fields = append(fields, "Name")
 	}
-	if updateChallengeCommand.Icon != nil {
-		challenge.Icon = *updateChallengeCommand.Icon
+	// изображения меняются только загрузкой файла: для URL из тела запроса варианты не построить,
+	// поэтому тот же URL игнорируется, а другой отклоняется
+	iconChanged, err := replacesImage("icon", challenge.Icon, command.Icon, command.IconVariants)
+	if err != nil {
+		return nil, err
 	}
-	if updateChallengeCommand.Image != nil {
-		challenge.Image = *updateChallengeCommand.Image
+	if iconChanged {
+		challenge.Icon = *command.Icon
+		challenge.IconVariants = command.IconVariants
+		fields = append(fields, "Icon", "IconVariants")
 	}
-	if updateChallengeCommand.Description != nil {
-		challenge.Description = *updateChallengeCommand.Description
+	imageChanged, err := replacesImage("image", challenge.Image, command.Image, command.ImageVariants)
+	if err != nil {
+		return nil, err
 	}
-	if updateChallengeCommand.StartDate != nil {
-		challenge.StartDate = *updateChallengeCommand.StartDate
+	if imageChanged {
+		challenge.Image = *command.Image
+		challenge.ImageVariants = command.ImageVariants
+		fields = append(fields, "Image", "ImageVariants")
 	}
-	if updateChallengeCommand.EndDate != nil {
-		challenge.EndDate = *updateChallengeCommand.EndDate
+	if command.Description != nil {
+		challenge.Description = *command.Description
+		fields = append(fields, "Description")
 	}
-	if updateChallengeCommand.Type != nil {
-		challenge.Type = *updateChallengeCommand.Type
+	if command.StartDate != nil {
+		challenge.StartDate = *command.StartDate
+		fields = append(fields, "StartDate")
 	}
-	if updateChallengeCommand.IsTeam != nil {
-		challenge.IsTeam = *updateChallengeCommand.IsTeam
+	if command.EndDate != nil {
+		challenge.EndDate = *command.EndDate
+		fields = append(fields, "EndDate")
 	}
-	if updateChallengeCommand.CreatorID != nil {
-		challenge.CreatorID = *updateChallengeCommand.CreatorID
+	if command.Type != nil {
+		challenge.Type = *command.Type
+		fields = append(fields, "Type")
+	}
+	if command.IsTeam != nil {
+		challenge.IsTeam = *command.IsTeam
+		fields = append(fields, "IsTeam")
+	}
+	if command.CreatorID != nil {
+		challenge.CreatorID = *command.CreatorID
+		fields = append(fields, "CreatorID")
+	}
+	if command.CoOrganizerIDs != nil {
+		challenge.CoOrganizerIDs = *command.CoOrganizerIDs
+		fields = append(fields, "CoOrganizerIDs")
+	}
+	// даты могут меняться по отдельности, поэтому порядок проверяется после применения изменений
+	if !challenge.EndDate.After(challenge.StartDate) {
+		return nil, fmt.Errorf("%w: %w", cqrs.ErrValidation,
+			validation.NewError("end_date", validation.CodeMustBeAfter, "start_date", "must be after start_date"))
+	}
+	// правила вида проверяются при его смене, чтобы не блокировать правку старых вызовов
+	if command.Type != nil || command.IsTeam != nil {
+		if err := challenge.CheckKindRules(); err != nil {
+			return nil, err
+		}
+	}
+	if len(fields) == 0 {
+		return challenge, nil
 	}
 
-	result, err := h.repo.Update(challenge)
+	var result *entity.AuthenticationChallenge
+	err = commitWithEvents(ctx, h.repo, h.publisher, func(repo repository_interface.ChallengeRepositoryInterface) ([]cqrs.Event, error) {
+		if result, err = repo.Update(ctx, *challenge, fields...); err != nil {
+			return nil, err
+		}
+		return []cqrs.Event{events.NewChallengeUpdated(result)}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
+
+// replacesImage сообщает, заменяет ли команда изображение field. Без вариантов (URL передан в теле,
+// а не загружен файлом) допускается только текущий URL
+func replacesImage(field string, current string, url *string, variants map[string]string) (bool, error) {
+	if url == nil {
+		return false, nil
+	}
+	if variants != nil {
+		return true, nil
+	}
+	if *url == current {
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: %w", cqrs.ErrValidation,
+		validation.NewError(field, validation.CodeInvalid, "", "must be uploaded as a file to be replaced"))
+}
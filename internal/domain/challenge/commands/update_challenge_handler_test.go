@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReplacesImage(t *testing.T) {
+	const current = "https://cdn.example.com/challenges/1.webp"
+	uploaded := "https://cdn.example.com/challenges/2.webp"
+	sameURL := current
+	otherURL := "https://example.com/other.png"
+	variants := map[string]string{"small": "https://cdn.example.com/challenges/2_small.webp"}
+
+	tests := []struct {
+		name     string
+		url      *string
+		variants map[string]string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "not passed"},
+		{name: "uploaded file", url: &uploaded, variants: variants, want: true},
+		{name: "current url in the body is ignored", url: &sameURL},
+		{name: "another url in the body is rejected", url: &otherURL, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := replacesImage("icon", current, tt.url, tt.variants)
+			if tt.wantErr != errors.Is(err, cqrs.ErrValidation) {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("replacesImage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateChallengeRequiresVersion(t *testing.T) {
+	_, err := (&UpdateChallengeHandler{}).Handle(context.Background(), &UpdateChallengeCommand{ChallengeID: 1})
+	if !errors.Is(err, entity.ErrVersionRequired) {
+		t.Fatalf("err = %v, want ErrVersionRequired", err)
+	}
+}
@@ -5,50 +5,61 @@
 // @contact.name API Support
 // @contact.url http://www.example.com/support
 // @contact.email support@example.com
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 
 package handlers
 
 import (
 	"challenge-service/config"
 	"challenge-service/internal/domain/challenge/commands"
+	"challenge-service/internal/domain/challenge/delievery/http/problem"
 	"challenge-service/internal/domain/challenge/entity"
 	"challenge-service/internal/domain/challenge/queries"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
-	"challenge-service/internal/infrastructure/lib/fabric"
+	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/lib/imaging"
 	"challenge-service/internal/infrastructure/lib/log"
-	"challenge-service/internal/infrastructure/lib/save_photo"
-	"context"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"challenge-service/internal/infrastructure/lib/validation"
 	"errors"
 	"github.com/gin-gonic/gin"
-	"io"
 	"log/slog"
 	"math/rand/v2"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type ChallengesHandlers struct {
-	cfg           *config.Config
-	log           *slog.Logger
-	handlerFabric *fabric.HandlerFabric
-	repo          repository_interface.ChallengeRepositoryInterface
+	cfg     *config.Config
+	log     *slog.Logger
+	bus     *cqrs.Bus
+	repo    repository_interface.ChallengeRepositoryInterface
+	storage storage.Storage
+	images  *imaging.Processor
 }
 
-func NewChallengesHandlers(cfg *config.Config, log *slog.Logger, handlerFabric *fabric.HandlerFabric,
-	repo repository_interface.ChallengeRepositoryInterface) *ChallengesHandlers {
+func NewChallengesHandlers(cfg *config.Config, log *slog.Logger, bus *cqrs.Bus,
+	repo repository_interface.ChallengeRepositoryInterface, storage storage.Storage,
+	images *imaging.Processor) *ChallengesHandlers {
 	return &ChallengesHandlers{
-		cfg:           cfg,
-		log:           log,
-		handlerFabric: handlerFabric,
-		repo:          repo,
+		cfg:     cfg,
+		log:     log,
+		bus:     bus,
+		repo:    repo,
+		storage: storage,
+		images:  images,
 	}
 }
 
-type PingResponse struct {
-	Message string `json:"message,omitempty"`
+// logger логгер текущего запроса с request_id, user_id и маршрутом
+func (h *ChallengesHandlers) logger(c *gin.Context) *slog.Logger {
+	return log.FromContext(c.Request.Context(), h.log)
 }
 
-type ErrorResponse struct {
+type PingResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
@@ -72,105 +83,177 @@ func (h *ChallengesHandlers) Ping(c *gin.Context) {
 // @Summary      Create a new challenge
 // @Description  Creates a new challenge with the provided data
 // @Tags         Challenges
+// @Security     BearerAuth
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        challenge  body  entity.AuthenticationChallenge  true  "Challenge Data"
-// @Param        image      formData  file  true  "Image File"
+// @Param        challenge  formData  string  true  "Challenge Data (JSON entity.AuthenticationChallenge)"
+// @Param        image      formData  file  true  "Image File (JPEG, PNG or WebP)"
+// @Param        icon       formData  file  true  "Icon File (JPEG, PNG or WebP)"
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
 // @Success      201  {object}  entity.AuthenticationChallenge // Изменен код успешного ответа
-// @Failure      400  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      413  {object}  problem.Problem
+// @Failure      415  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges [post]
 func (h *ChallengesHandlers) CreateChallenge(c *gin.Context) {
 	var challenge entity.AuthenticationChallenge
-	if err := c.ShouldBindJSON(&challenge); err != nil {
-		h.log.Error("Error binding JSON:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindMultipartJSON(c, "challenge", &challenge); err != nil {
+		h.writeBadRequest(c, err, "malformed request body")
 		return
 	}
-	image, header, err := c.Request.FormFile("image")
+	var uploaded uploadedFiles
+	urlImage, imageVariants, err := h.uploadImage(c, "image", imaging.ImageVariants, &uploaded)
 	if err != nil {
-		h.log.Error("Error retrieving image:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Image not provided"})
+		h.discardUploads(c.Request.Context(), uploaded)
+		h.writeUploadError(c, "image", err)
 		return
 	}
-	defer image.Close()
-	icon, headerIcon, err := c.Request.FormFile("icon")
+	urlIcon, iconVariants, err := h.uploadImage(c, "icon", imaging.IconVariants, &uploaded)
 	if err != nil {
-		h.log.Error("Error retrieving icon:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Icon not provided"})
-	}
-	defer icon.Close()
-	imageBytes, err := io.ReadAll(image)
-	if err != nil {
-		h.log.Error("Error reading file:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read image"})
+		h.discardUploads(c.Request.Context(), uploaded)
+		h.writeUploadError(c, "icon", err)
 		return
 	}
-	iconBytes, err := io.ReadAll(icon)
+	challenge.Image = urlImage
+	challenge.Icon = urlIcon
+	randomID := rand.Int64()
+	command := commands.NewCreateChallengeCommand(randomID, &challenge.Name, &challenge.Icon, &challenge.Image,
+		&challenge.Description, &challenge.StartDate, &challenge.EndDate, &challenge.Type, &challenge.IsTeam,
+		&challenge.CreatorID)
+	command.CoOrganizerIDs = challenge.CoOrganizerIDs
+	command.IconVariants = iconVariants
+	command.ImageVariants = imageVariants
+
+	result, err := cqrs.Dispatch[*commands.CreateChallengeCommand, *entity.AuthenticationChallenge](
+		c.Request.Context(), h.bus, command)
 	if err != nil {
-		h.log.Error("Error reading file:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read icon"})
+		h.discardUploads(c.Request.Context(), uploaded)
+		h.writeError(c, err)
 		return
 	}
-	s3Client := save_photo.NewS3Client(h.cfg, h.log)
-	urlImage, err := s3Client.UploadFile(imageBytes, header.Filename)
+	h.respond(c, http.StatusCreated, result) // Изменен код успешного ответа
+}
+
+// GetAllChallenges
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Retrieve challenges
+// @Description  Fetches a page of challenges filtered and sorted by the query parameters
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Produce      json
+// @Param        limit        query  int     false  "Page size (default 20, max 100)"
+// @Param        cursor       query  string  false  "next_cursor from the previous page"
+// @Param        sort         query  string  false  "Sort key" Enums(start_date, end_date, name, participants)
+// @Param        order        query  string  false  "Sort order" Enums(asc, desc)
+// @Param        type         query  string  false  "Challenge type" Enums(family, personal, group)
+// @Param        is_team      query  bool    false  "Team challenges only"
+// @Param        is_finished  query  bool    false  "Finished challenges only"
+// @Param        creator_id   query  int64   false  "Creator ID"
+// @Param        start_from   query  string  false  "Start date from (RFC 3339)"
+// @Param        start_to     query  string  false  "Start date to (RFC 3339)"
+// @Param        end_from     query  string  false  "End date from (RFC 3339)"
+// @Param        end_to       query  string  false  "End date to (RFC 3339)"
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  repository_interface.ChallengePage
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges [get]
+func (h *ChallengesHandlers) GetAllChallenges(c *gin.Context) {
+	params, err := bindListParams(c)
 	if err != nil {
-		h.log.Error("error while saving photo:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.writeParamsError(c, err)
 		return
 	}
-	urlIcon, err := s3Client.UploadFile(iconBytes, headerIcon.Filename)
-	challenge.Image = urlImage
-	challenge.Icon = urlIcon
+	query := queries.NewFindByParamsQuery(rand.Int64(), params)
+	result, err := cqrs.Ask[*queries.FindByParamsQuery, *repository_interface.ChallengePage](c.Request.Context(), h.bus, query)
 	if err != nil {
-		h.log.Error("error while saving icon:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.writeError(c, err)
+		return
 	}
-	randomID := rand.Int64()
-	command := commands.NewCreateChallengeCommand(randomID, &challenge.Name, &challenge.Icon, &challenge.Description,
-		&challenge.EndDate, &challenge.Type, &challenge.IsTeam, &challenge.CreatorID)
+	h.respond(c, http.StatusOK, result)
+}
 
-	handler, err := h.handlerFabric.GetCommandHandler(command)
+// SearchChallenges
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Search challenges
+// @Description  Full-text search over challenge names and descriptions (Russian and English).
+// @Description  Results are ranked by relevance, matches in highlights are wrapped in <mark>
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Produce      json
+// @Param        q        query  string  true   "Search query, supports quotes, OR and -word"
+// @Param        type     query  string  false  "Challenge type" Enums(family, personal, group)
+// @Param        is_team  query  bool    false  "Team challenges only"
+// @Param        limit    query  int     false  "Page size (default 20, max 100)"
+// @Param        offset   query  int     false  "Number of results to skip"
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  repository_interface.ChallengeSearchPage
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/search [get]
+func (h *ChallengesHandlers) SearchChallenges(c *gin.Context) {
+	params, err := bindSearchParams(c)
 	if err != nil {
-		h.log.Error("Error getting command handler:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeParamsError(c, err)
 		return
 	}
-	result, err := handler.Handle(context.Background(), command)
+	query := queries.NewSearchChallengesQuery(rand.Int64(), params)
+	result, err := cqrs.Ask[*queries.SearchChallengesQuery, *repository_interface.ChallengeSearchPage](c.Request.Context(), h.bus, query)
 	if err != nil {
-		h.log.Error("Error handling command:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, result) // Изменен код успешного ответа
+	h.respond(c, http.StatusOK, result)
 }
 
-// GetAllChallenges
+// GetChallenge
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @Summary      Retrieve all challenges
-// @Description  Fetches a list of all challenges
+// @Summary      Get a challenge
+// @Description  Returns a challenge with its ETag. Responds 304 if If-None-Match contains the current ETag
 // @Tags         Challenges
+// @Security     BearerAuth
 // @Produce      json
-// @Success      200  {array}  entity.AuthenticationChallenge
-// @Failure      500  {object}  ErrorResponse
-// @Router       /challenges [get]
-func (h *ChallengesHandlers) GetAllChallenges(c *gin.Context) {
-	query := queries.NewFindAllQuery()
-	handler, err := h.handlerFabric.GetQueryHandler(query)
+// @Param        id                path    int64   true   "Challenge ID"
+// @Param        If-None-Match     header  string  false  "ETag of the cached challenge"
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Header       200  {string}  ETag  "Challenge version"
+// @Success      304
+// @Failure      400  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id} [get]
+func (h *ChallengesHandlers) GetChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		h.log.Error("Error getting query handler:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeBadRequest(c, err, "invalid challenge ID")
 		return
 	}
-	result, err := handler.Handle(context.Background(), query)
+	query := queries.NewGetChallengeQuery(rand.Int64(), challengeID)
+	result, err := cqrs.Ask[*queries.GetChallengeQuery, *entity.AuthenticationChallenge](c.Request.Context(), h.bus, query)
 	if err != nil {
-		h.log.Error("Error handling query:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	etag := challengeETag(result.Version)
+	if !noneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Header("ETag", etag)
+		c.Header("Vary", "Accept-Language")
+		c.Status(http.StatusNotModified)
+		return
+	}
+	h.respond(c, http.StatusOK, result)
 }
 
 // UpdateChallenge
@@ -178,78 +261,82 @@ func (h *ChallengesHandlers) GetAllChallenges(c *gin.Context) {
 // @in header
 // @name Authorization
 // @Summary      Update an existing challenge
-// @Description  Updates the details of an existing challenge
+// @Description  Updates only the fields present in the request. If-Match with the ETag of the challenge
+// @Description  is required: the update is rejected with 412 if the challenge was changed since it was read
+// @Description  and with 428 without the header. If-Match: * updates any version.
+// @Description  The icon and image are replaced only by uploading a file, which also regenerates their
+// @Description  resized variants. An icon or image URL in the JSON data must equal the current one,
+// @Description  any other URL is rejected with 422
 // @Tags         Challenges
+// @Security     BearerAuth
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param        id         path      int64                      true  "Challenge ID"
-// @Param        challenge  body      entity.AuthenticationChallenge false "Updated Challenge Data"
-// @Param        image      formData  file  false  "New Image File"
-// @Param        icon       formData  file  false  "New Icon File"
-// @Success      200  {object}  interface{}
-// @Failure      400  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Param        If-Match   header    string                     true  "ETag of the challenge version the update is based on"
+// @Param        challenge  formData  string  false "Updated Challenge Data (JSON commands.UpdateChallengeCommand)"
+// @Param        image      formData  file  false  "New Image File (JPEG, PNG or WebP)"
+// @Param        icon       formData  file  false  "New Icon File (JPEG, PNG or WebP)"
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Header       200  {string}  ETag  "Challenge version"
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      413  {object}  problem.Problem
+// @Failure      415  {object}  problem.Problem
+// @Failure      412  {object}  problem.Problem
+// @Failure      428  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/{id} [put]
 func (h *ChallengesHandlers) UpdateChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
 	var updateCommand commands.UpdateChallengeCommand
-	if err := c.ShouldBindJSON(&updateCommand); err != nil {
-		h.log.Error("Error binding JSON:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := bindMultipartJSON(c, "challenge", &updateCommand); err != nil {
+		h.writeBadRequest(c, err, "malformed request body")
 		return
 	}
+	// вызов определяется путем, challenge_id в теле оставлен для совместимости
+	if updateCommand.ChallengeID != 0 && updateCommand.ChallengeID != challengeID {
+		h.writeError(c, validation.NewError("challenge_id", validation.CodeInvalid, "",
+			"must match the challenge ID in the path"))
+		return
+	}
+	updateCommand.ChallengeID = challengeID
+	updateCommand.ExpectedVersions, updateCommand.AnyVersion = parseIfMatch(c.GetHeader("If-Match"))
 
-	s3Client := save_photo.NewS3Client(h.cfg, h.log)
-
-	// Проверка и загрузка нового изображения, если оно предоставлено
-	if image, header, err := c.Request.FormFile("image"); err == nil {
-		defer image.Close()
-		imageBytes, err := io.ReadAll(image)
-		if err != nil {
-			h.log.Error("Error reading image file:", log.Err(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read image"})
-			return
-		}
-		urlImage, err := s3Client.UploadFile(imageBytes, header.Filename)
-		if err != nil {
-			h.log.Error("Error uploading image to S3:", log.Err(err))
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
+	// Загрузка нового изображения и иконки, если они предоставлены
+	var uploaded uploadedFiles
+	if urlImage, variants, err := h.uploadImage(c, "image", imaging.ImageVariants, &uploaded); err == nil {
 		updateCommand.Image = &urlImage
+		updateCommand.ImageVariants = variants
+	} else if !errors.Is(err, http.ErrMissingFile) {
+		h.discardUploads(c.Request.Context(), uploaded)
+		h.writeUploadError(c, "image", err)
+		return
 	}
-
-	// Проверка и загрузка новой иконки, если она предоставлена
-	if icon, headerIcon, err := c.Request.FormFile("icon"); err == nil {
-		defer icon.Close()
-		iconBytes, err := io.ReadAll(icon)
-		if err != nil {
-			h.log.Error("Error reading icon file:", log.Err(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read icon"})
-			return
-		}
-		urlIcon, err := s3Client.UploadFile(iconBytes, headerIcon.Filename)
-		if err != nil {
-			h.log.Error("Error uploading icon to S3:", log.Err(err))
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
+	if urlIcon, variants, err := h.uploadImage(c, "icon", imaging.IconVariants, &uploaded); err == nil {
 		updateCommand.Icon = &urlIcon
+		updateCommand.IconVariants = variants
+	} else if !errors.Is(err, http.ErrMissingFile) {
+		h.discardUploads(c.Request.Context(), uploaded)
+		h.writeUploadError(c, "icon", err)
+		return
 	}
 
 	// Обработка команды обновления
-	handler, err := h.handlerFabric.GetCommandHandler(&updateCommand)
+	result, err := cqrs.Dispatch[*commands.UpdateChallengeCommand, *entity.AuthenticationChallenge](
+		c.Request.Context(), h.bus, &updateCommand)
 	if err != nil {
-		h.log.Error("Error getting command handler:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.discardUploads(c.Request.Context(), uploaded)
+		h.writeError(c, err)
 		return
 	}
-	result, err := handler.Handle(context.Background(), &updateCommand)
-	if err != nil {
-		h.log.Error("Error handling command:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, result)
+	h.respond(c, http.StatusOK, result)
 }
 
 type DeleteChallengeResponse struct {
@@ -261,36 +348,90 @@ type DeleteChallengeResponse struct {
 // @in header
 // @name Authorization
 // @Summary      Delete a challenge
-// @Description  Removes a challenge by its ID
+// @Description  Moves a challenge to the trash. It can be restored until the trash retention period expires
 // @Tags         Challenges
+// @Security     BearerAuth
 // @Param        id   path     int64  true  "Challenge ID"
 // @Success      200  {object}  DeleteChallengeResponse
-// @Failure      400  {object}  ErrorResponse
-// @Failure      500  {object}  ErrorResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/{id} [delete]
 func (h *ChallengesHandlers) DeleteChallenge(c *gin.Context) {
 	idParam := c.Param("id")
 	challengeID, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		h.log.Error("Error parsing challenge ID:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid challenge ID"})
+		h.writeBadRequest(c, err, "invalid challenge ID")
 		return
 	}
 
 	command := commands.NewDeleteChallengeCommand(rand.Int64(), challengeID)
-	handler, err := h.handlerFabric.GetCommandHandler(command)
+	_, err = cqrs.Dispatch[*commands.DeleteChallengeCommand, struct{}](c.Request.Context(), h.bus, command)
 	if err != nil {
-		h.log.Error("Error getting command handler:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeError(c, err)
 		return
 	}
-	_, err = handler.Handle(context.Background(), command)
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// GetTrash
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Get deleted challenges
+// @Description  Returns challenges of the current user that are in the trash, most recently deleted first
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {array}   entity.AuthenticationChallenge
+// @Failure      401  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/trash [get]
+func (h *ChallengesHandlers) GetTrash(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		problem.Write(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "authentication required"))
+		return
+	}
+	query := queries.NewListTrashQuery(rand.Int64(), userID.(int64))
+	result, err := cqrs.Ask[*queries.ListTrashQuery, []*entity.AuthenticationChallenge](c.Request.Context(), h.bus, query)
 	if err != nil {
-		h.log.Error("Error handling command:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	h.respond(c, http.StatusOK, result)
+}
+
+// RestoreChallenge
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Restore challenge
+// @Description  Restores a challenge from the trash
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Param        id  path  int64  true  "Challenge ID"
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Header       200  {string}  ETag  "Challenge version"
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id}/restore [post]
+func (h *ChallengesHandlers) RestoreChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	handleCommand[*entity.AuthenticationChallenge](h, c, commands.NewRestoreChallengeCommand(rand.Int64(), challengeID), http.StatusOK)
 }
 
 // GetAllChallengesFromUser
@@ -300,27 +441,45 @@ func (h *ChallengesHandlers) DeleteChallenge(c *gin.Context) {
 // @Summary      Get challenges for a user
 // @Description  Retrieves all challenges associated with a specific user
 // @Tags         Challenges
-// @Param        user_id  path     string  true  "User ID"
+// @Security     BearerAuth
+// @Param        user_id      path   int64   true   "User ID"
+// @Param        limit        query  int     false  "Page size (default 20, max 100)"
+// @Param        cursor       query  string  false  "next_cursor from the previous page"
+// @Param        sort         query  string  false  "Sort key" Enums(start_date, end_date, name, participants)
+// @Param        order        query  string  false  "Sort order" Enums(asc, desc)
+// @Param        type         query  string  false  "Challenge type" Enums(family, personal, group)
+// @Param        is_team      query  bool    false  "Team challenges only"
+// @Param        is_finished  query  bool    false  "Finished challenges only"
+// @Param        creator_id   query  int64   false  "Creator ID"
+// @Param        start_from   query  string  false  "Start date from (RFC 3339)"
+// @Param        start_to     query  string  false  "Start date to (RFC 3339)"
+// @Param        end_from     query  string  false  "End date from (RFC 3339)"
+// @Param        end_to       query  string  false  "End date to (RFC 3339)"
 // @Produce      json
-// @Success      200  {array}  entity.AuthenticationChallenge
-// @Failure      500  {object}  ErrorResponse
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  repository_interface.ChallengePage
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/user/{user_id} [get]
 func (h *ChallengesHandlers) GetAllChallengesFromUser(c *gin.Context) {
-	userID := c.Param("user_id")
-	query := queries.NewGetAllChallengesFromUserQuery(rand.Int64(), userID)
-	handler, err := h.handlerFabric.GetQueryHandler(query)
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
 	if err != nil {
-		h.log.Error("Error getting query handler:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeBadRequest(c, err, "invalid user ID")
 		return
 	}
-	result, err := handler.Handle(context.Background(), query)
+	params, err := bindListParams(c)
 	if err != nil {
-		h.log.Error("Error handling query:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeParamsError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	query := queries.NewGetAllChallengesFromUserQuery(rand.Int64(), userID, params)
+	result, err := cqrs.Ask[*queries.GetAllChallengesFromUserQuery, *repository_interface.ChallengePage](c.Request.Context(), h.bus, query)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.respond(c, http.StatusOK, result)
 }
 
 // GetAllChallengesFromTeam
@@ -330,27 +489,49 @@ func (h *ChallengesHandlers) GetAllChallengesFromUser(c *gin.Context) {
 // @Summary      Get challenges for a team
 // @Description  Retrieves all challenges associated with a specific team
 // @Tags         Challenges
-// @Param        team_id  path     string  true  "Team ID"
+// @Security     BearerAuth
+// @Param        team_id      path   int64   true   "Team ID"
+// @Param        limit        query  int     false  "Page size (default 20, max 100)"
+// @Param        cursor       query  string  false  "next_cursor from the previous page"
+// @Param        sort         query  string  false  "Sort key" Enums(start_date, end_date, name, participants)
+// @Param        order        query  string  false  "Sort order" Enums(asc, desc)
+// @Param        type         query  string  false  "Challenge type" Enums(family, personal, group)
+// @Param        is_team      query  bool    false  "Team challenges only"
+// @Param        is_finished  query  bool    false  "Finished challenges only"
+// @Param        creator_id   query  int64   false  "Creator ID"
+// @Param        start_from   query  string  false  "Start date from (RFC 3339)"
+// @Param        start_to     query  string  false  "Start date to (RFC 3339)"
+// @Param        end_from     query  string  false  "End date from (RFC 3339)"
+// @Param        end_to       query  string  false  "End date to (RFC 3339)"
 // @Produce      json
-// @Success      200  {array}  entity.AuthenticationChallenge
-// @Failure      500  {object}  ErrorResponse
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  repository_interface.ChallengePage
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/team/{team_id} [get]
 func (h *ChallengesHandlers) GetAllChallengesFromTeam(c *gin.Context) {
-	teamID := c.Param("team_id")
-	query := queries.NewGetAllChallengesFromTeamQuery(rand.Int64(), teamID)
-	handler, err := h.handlerFabric.GetQueryHandler(query)
+	teamID, err := strconv.ParseInt(c.Param("team_id"), 10, 64)
 	if err != nil {
-		h.log.Error("Error getting query handler:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeBadRequest(c, err, "invalid team ID")
 		return
 	}
-	result, err := handler.Handle(context.Background(), query)
+	params, err := bindListParams(c)
 	if err != nil {
-		h.log.Error("Error handling query:", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeParamsError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	query := queries.NewGetAllChallengesFromTeamQuery(rand.Int64(), teamID, params)
+	result, err := cqrs.Ask[*queries.GetAllChallengesFromTeamQuery, *repository_interface.ChallengePage](c.Request.Context(), h.bus, query)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+	h.respond(c, http.StatusOK, result)
+}
+
+type RegisterRequest struct {
+	ChallengeID int64 `json:"challenge_id" binding:"required"`
 }
 
 // RegisterUser
@@ -358,33 +539,34 @@ func (h *ChallengesHandlers) GetAllChallengesFromTeam(c *gin.Context) {
 // @in header
 // @name Authorization
 // @Summary      Register user on challenge
-// @Description  Register user on challenge
+// @Description  Register user on challenge. Only scheduled and active challenges accept registrations
 // @Tags         Challenges
+// @Security     BearerAuth
+// @Accept       json
 // @Produce      json
-// @Success      200  {array}  entity.AuthenticationParticipant
-// @Failure      500  {object}  ErrorResponse
+// @Param        request  body  RegisterRequest  true  "Challenge to register on"
+// @Success      200  {object}  entity.AuthenticationParticipant
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/user/register [post]
 func (h *ChallengesHandlers) RegisterUser(c *gin.Context) {
 	userID, ok := c.Get("user_id")
-	var challenge entity.AuthenticationChallenge
-	if err := c.ShouldBindJSON(&challenge); err != nil {
-		h.log.Error("Error binding JSON:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
 	if !ok {
-		h.log.Error("not auth", log.Err(errors.New("not authorized")))
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authorized"})
+		problem.Write(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "authentication required"))
 		return
 	}
-	id := userID.(int64)
-	response, err := h.repo.RegisterUserOnChallenge(id, challenge)
-	if err != nil {
-		h.log.Error("error while register on challenge", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "error while register on challenge"})
+	var request RegisterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeBadRequest(c, err, "malformed request body")
 		return
 	}
-	c.JSON(http.StatusOK, response)
+	command := commands.NewRegisterParticipantCommand(rand.Int64(), request.ChallengeID, userID.(int64), 0)
+	handleCommand[*entity.AuthenticationParticipant](h, c, command, http.StatusOK)
 }
 
 // RegisterTeam
@@ -392,40 +574,41 @@ func (h *ChallengesHandlers) RegisterUser(c *gin.Context) {
 // @in header
 // @name Authorization
 // @Summary      Register team on challenge
-// @Description  Register team on challenge
+// @Description  Register team on challenge. Only scheduled and active team challenges accept registrations
+// @Description  The caller must be a member of the team (team_ids token claim)
 // @Tags         Challenges
-// @Param        team_id  path     string  true  "Team ID"
+// @Security     BearerAuth
+// @Accept       json
 // @Produce      json
-// @Success      200  {array}  entity.AuthenticationParticipant
-// @Failure      500  {object}  ErrorResponse
+// @Param        team_id  path  string           true  "Team ID"
+// @Param        request  body  RegisterRequest  true  "Challenge to register on"
+// @Success      200  {object}  entity.AuthenticationParticipant
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/team/register/{team_id} [post]
 func (h *ChallengesHandlers) RegisterTeam(c *gin.Context) {
-	_, ok := c.Get("user_id")
+	userID, ok := c.Get("user_id")
 	if !ok {
-		h.log.Error("not auth", log.Err(errors.New("not authorized")))
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authorized"})
+		problem.Write(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "authentication required"))
 		return
 	}
-	teamID := c.Param("team_id")
-	var challenge entity.AuthenticationChallenge
-	if err := c.ShouldBindJSON(&challenge); err != nil {
-		h.log.Error("Error binding JSON:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	id, err := strconv.ParseInt(teamID, 10, 64)
+	teamID, err := strconv.ParseInt(c.Param("team_id"), 10, 64)
 	if err != nil {
-		h.log.Error("Error parsing team ID:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.writeBadRequest(c, err, "invalid team ID")
 		return
 	}
-	response, err := h.repo.RegisterTeamOnChallenge(id, challenge)
-	if err != nil {
-		h.log.Error("error while register on challenge", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "error while register on challenge"})
+	var request RegisterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeBadRequest(c, err, "malformed request body")
 		return
 	}
-	c.JSON(http.StatusOK, response)
+	command := commands.NewRegisterParticipantCommand(rand.Int64(), request.ChallengeID, userID.(int64), teamID)
+	handleCommand[*entity.AuthenticationParticipant](h, c, command, http.StatusOK)
 }
 
 // CloseChallenge
@@ -433,23 +616,242 @@ func (h *ChallengesHandlers) RegisterTeam(c *gin.Context) {
 // @in header
 // @name Authorization
 // @Summary      Close challenge
-// @Description  This method closes challenge and send message to winner
+// @Description  Moves an active challenge to the finished state
 // @Tags         Challenges
+// @Security     BearerAuth
 // @Param        challenge_id  path     string  true  "Challenge ID"
 // @Produce      json
-// @Success      200  {array}  entity.AuthenticationChallenge
-// @Failure      500  {object}  ErrorResponse
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /challenges/close/{challenge_id} [post]
 func (h *ChallengesHandlers) CloseChallenge(c *gin.Context) {
 	challengeID, err := strconv.ParseInt(c.Param("challenge_id"), 10, 64)
 	if err != nil {
-		h.log.Error("Error parsing challenge ID:", log.Err(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	handleCommand[*entity.AuthenticationChallenge](h, c, commands.NewCloseChallengeCommand(rand.Int64(), challengeID), http.StatusOK)
+}
+
+// PublishChallenge
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Publish challenge
+// @Description  Moves a draft challenge to scheduled, or to active if its start date has passed
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Param        id  path  int64  true  "Challenge ID"
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id}/publish [post]
+func (h *ChallengesHandlers) PublishChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	handleCommand[*entity.AuthenticationChallenge](h, c, commands.NewPublishChallengeCommand(rand.Int64(), challengeID), http.StatusOK)
+}
+
+// CancelChallenge
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Cancel challenge
+// @Description  Cancels a draft, scheduled or active challenge
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Param        id  path  int64  true  "Challenge ID"
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id}/cancel [post]
+func (h *ChallengesHandlers) CancelChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	handleCommand[*entity.AuthenticationChallenge](h, c, commands.NewCancelChallengeCommand(rand.Int64(), challengeID), http.StatusOK)
+}
+
+// ArchiveChallenge
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Archive challenge
+// @Description  Archives a finished or cancelled challenge
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Param        id  path  int64  true  "Challenge ID"
+// @Produce      json
+// @Param        Accept-Language  header  string  false  "Language of type_label (ru, en), ru by default"
+// @Success      200  {object}  entity.AuthenticationChallenge
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id}/archive [post]
+func (h *ChallengesHandlers) ArchiveChallenge(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	handleCommand[*entity.AuthenticationChallenge](h, c, commands.NewArchiveChallengeCommand(rand.Int64(), challengeID), http.StatusOK)
+}
+
+// handleCommand выполняет команду и пишет ее результат типа R в ответ
+func handleCommand[R any, C cqrs.Command](h *ChallengesHandlers, c *gin.Context, command C, status int) {
+	result, err := cqrs.Dispatch[C, R](c.Request.Context(), h.bus, command)
+	if err != nil {
+		h.writeError(c, err)
+		return
 	}
-	response, err := h.repo.CloseChallenge(challengeID)
+	h.respond(c, status, result)
+}
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type RecordProgressRequest struct {
+	Value     float64   `json:"value" binding:"required"`
+	Unit      string    `json:"unit"`
+	Timestamp time.Time `json:"timestamp"`
+	Note      string    `json:"note"`
+	TeamID    int64     `json:"team_id"`
+}
+
+// RecordProgress
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Record progress check-in
+// @Description  Appends a progress check-in of the current participant to the challenge
+// @Description  value must be in (0, 100000]. timestamp defaults to now, it cannot be in the future
+// @Description  or more than 48 hours in the past and must be within the challenge dates.
+// @Description  With team_id the check-in goes to the team, the caller must be a member of it (team_ids token claim)
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id        path  int64                  true  "Challenge ID"
+// @Param        check_in  body  RecordProgressRequest  true  "Check-in"
+// @Success      200  {object}  entity.AuthenticationParticipant
+// @Failure      400  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      401  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id}/progress [post]
+func (h *ChallengesHandlers) RecordProgress(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		problem.Write(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "authentication required"))
+		return
+	}
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		h.log.Error("error while closing challenge", log.Err(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "error while closing challenge"})
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	var request RecordProgressRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeBadRequest(c, err, "malformed request body")
+		return
+	}
+
+	command := commands.NewRecordProgressCommand(rand.Int64(), challengeID, userID.(int64), request.TeamID,
+		request.Value, request.Unit, request.Timestamp, request.Note)
+	handleCommand[*entity.AuthenticationParticipant](h, c, command, http.StatusOK)
+}
+
+// GetLeaderboard
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @Summary      Get challenge leaderboard
+// @Description  Ranks participants by accumulated progress. Team challenges rank teams, personal challenges rank users
+// @Tags         Challenges
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id       path   int64  true   "Challenge ID"
+// @Param        limit    query  int    false  "Page size (default 10, max 100)"
+// @Param        offset   query  int    false  "Number of top entries to skip, next_offset from the previous page"
+// @Param        team_id  query  int64  false  "Caller's team for team challenges, the caller must be a member of it"
+// @Success      200  {object}  entity.Leaderboard
+// @Failure      400  {object}  problem.Problem
+// @Failure      403  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /challenges/{id}/leaderboard [get]
+func (h *ChallengesHandlers) GetLeaderboard(c *gin.Context) {
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.writeBadRequest(c, err, "invalid challenge ID")
+		return
+	}
+	limit := defaultLeaderboardLimit
+	if rawLimit := c.Query("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			h.writeError(c, validation.NewError("limit", validation.CodeInvalid, "", "must be a positive integer"))
+			return
+		}
+		limit = min(limit, maxLeaderboardLimit)
+	}
+	var offset int
+	if rawOffset := c.Query("offset"); rawOffset != "" {
+		offset, err = strconv.Atoi(rawOffset)
+		if err != nil || offset < 0 {
+			h.writeError(c, validation.NewError("offset", validation.CodeInvalid, "", "must be a non-negative integer"))
+			return
+		}
+	}
+	var teamID int64
+	if rawTeamID := c.Query("team_id"); rawTeamID != "" {
+		teamID, err = strconv.ParseInt(rawTeamID, 10, 64)
+		if err != nil {
+			h.writeError(c, validation.NewError("team_id", validation.CodeInvalid, "", "must be an integer"))
+			return
+		}
+	}
+	var userID int64
+	if value, ok := c.Get("user_id"); ok {
+		userID = value.(int64)
+	}
+
+	query := queries.NewGetLeaderboardQuery(rand.Int64(), challengeID, limit, offset, userID, teamID)
+	result, err := cqrs.Ask[*queries.GetLeaderboardQuery, *entity.Leaderboard](c.Request.Context(), h.bus, query)
+	if err != nil {
+		h.writeError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, response)
+	h.respond(c, http.StatusOK, result)
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// challengeETag сильный ETag вызова, меняется вместе с его версией
+func challengeETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch версии из заголовка If-Match, anyVersion - заголовок равен "*". Без заголовка возвращается nil.
+// Слабые и нераспознанные теги по RFC 9110 не совпадают ни с одной версией,
+// поэтому заголовок только из них дает пустой список и ответ 412
+func parseIfMatch(header string) (versions []int64, anyVersion bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return nil, false
+	}
+	if header == "*" {
+		return nil, true
+	}
+	versions = []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(tag), `"`)
+		if !ok {
+			continue
+		}
+		value, ok = strings.CutSuffix(value, `"`)
+		if !ok {
+			continue
+		}
+		if version, err := strconv.ParseInt(value, 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false
+}
+
+// noneMatch проверяет If-None-Match: true, если ни один тег не совпадает с etag при слабом сравнении
+func noneMatch(header string, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return true
+	}
+	if header == "*" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChallengeETag(t *testing.T) {
+	if got := challengeETag(42); got != `"42"` {
+		t.Fatalf("challengeETag(42) = %s, want \"42\"", got)
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    []int64
+		wantAny bool
+	}{
+		{name: "absent", header: "", want: nil},
+		{name: "blank", header: "  ", want: nil},
+		{name: "any", header: "*", want: nil, wantAny: true},
+		{name: "single", header: `"3"`, want: []int64{3}},
+		{name: "list with spaces", header: ` "3" ,"5"`, want: []int64{3, 5}},
+		{name: "weak tag never matches", header: `W/"3"`, want: []int64{}},
+		{name: "weak and strong", header: `W/"3", "4"`, want: []int64{4}},
+		{name: "unquoted", header: `3`, want: []int64{}},
+		{name: "unterminated", header: `"3`, want: []int64{}},
+		{name: "not a version", header: `"abc", "7"`, want: []int64{7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotAny := parseIfMatch(tt.header)
+			if (got == nil) != (tt.want == nil) || !slices.Equal(got, tt.want) || gotAny != tt.wantAny {
+				t.Fatalf("parseIfMatch(%q) = %#v, %v, want %#v, %v", tt.header, got, gotAny, tt.want, tt.wantAny)
+			}
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	etag := challengeETag(7)
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "absent", header: "", want: true},
+		{name: "any", header: "*", want: false},
+		{name: "same tag", header: `"7"`, want: false},
+		{name: "weak comparison", header: `W/"7"`, want: false},
+		{name: "in a list", header: `"5", "7"`, want: false},
+		{name: "other tags", header: `"5", W/"6"`, want: true},
+		{name: "unquoted", header: `7`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := noneMatch(tt.header, etag); got != tt.want {
+				t.Fatalf("noneMatch(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"challenge-service/internal/domain/challenge/delievery/http/problem"
+	"challenge-service/internal/domain/challenge/domainerr"
+	"challenge-service/internal/infrastructure/lib/validation"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// writeError переводит ошибки обработчиков команд и запросов в ответ application/problem+json
+func (h *ChallengesHandlers) writeError(c *gin.Context, err error) {
+	problem.Error(c, h.log, err)
+}
+
+// writeBadRequest отвечает на запрос, который не удалось разобрать, без деталей разбора
+func (h *ChallengesHandlers) writeBadRequest(c *gin.Context, err error, detail string) {
+	problem.BadRequest(c, h.log, err, detail)
+}
+
+// writeParamsError отвечает на ошибку разбора query-параметров: доменные ошибки и ошибки полей
+// передаются клиенту, ошибки преобразования значений - без деталей
+func (h *ChallengesHandlers) writeParamsError(c *gin.Context, err error) {
+	var fields validation.Errors
+	if _, ok := domainerr.From(err); ok || errors.As(err, &fields) {
+		h.writeError(c, err)
+		return
+	}
+	h.writeBadRequest(c, err, "invalid query parameters")
+}
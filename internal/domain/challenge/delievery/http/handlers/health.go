@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"challenge-service/internal/infrastructure/health"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HealthHandlers struct {
+	checker *health.Checker
+}
+
+func NewHealthHandlers(checker *health.Checker) *HealthHandlers {
+	return &HealthHandlers{checker: checker}
+}
+
+// Healthz
+// @Summary      Liveness probe
+// @Description  Responds 200 while the process is running, dependencies are not checked
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} health.Report
+// @Router       /healthz [get]
+func (h *HealthHandlers) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Report{Status: health.StatusOK, Checks: []health.CheckResult{}})
+}
+
+// Readyz
+// @Summary      Readiness probe
+// @Description  Checks the database, the storage backend and the notification service.
+// @Description  Responds 200 when all dependencies are ok or only optional ones fail (status degraded), 503 otherwise
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} health.Report
+// @Failure      503  {object} health.Report
+// @Router       /readyz [get]
+func (h *HealthHandlers) Readyz(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == health.StatusFail {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/lib/validation"
+	"github.com/gin-gonic/gin"
+	"strings"
+	"time"
+)
+
+// ListChallengesRequest параметры выдачи списков вызовов. Даты передаются в RFC 3339
+type ListChallengesRequest struct {
+	Limit      int        `form:"limit"`
+	Cursor     string     `form:"cursor"`
+	Sort       string     `form:"sort"`
+	Order      string     `form:"order"`
+	Type       *string    `form:"type"`
+	IsTeam     *bool      `form:"is_team"`
+	IsFinished *bool      `form:"is_finished"`
+	CreatorID  *int64     `form:"creator_id"`
+	StartFrom  *time.Time `form:"start_from"`
+	StartTo    *time.Time `form:"start_to"`
+	EndFrom    *time.Time `form:"end_from"`
+	EndTo      *time.Time `form:"end_to"`
+}
+
+// bindListParams собирает фильтры, сортировку и курсор из query-параметров запроса.
+// Допустимость значений проверяет валидация запроса на шине
+func bindListParams(c *gin.Context) (*repository_interface.AuthenticationChallengeParams, error) {
+	var request ListChallengesRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		return nil, err
+	}
+	params := &repository_interface.AuthenticationChallengeParams{
+		Type:       request.Type,
+		IsTeam:     request.IsTeam,
+		IsFinished: request.IsFinished,
+		CreatorID:  request.CreatorID,
+		StartFrom:  request.StartFrom,
+		StartTo:    request.StartTo,
+		EndFrom:    request.EndFrom,
+		EndTo:      request.EndTo,
+		SortBy:     request.Sort,
+		Limit:      request.Limit,
+	}
+	if params.SortBy == "" {
+		params.SortBy = repository_interface.SortByStartDate
+	}
+	switch request.Order {
+	case "", "asc":
+	case "desc":
+		params.SortDesc = true
+	default:
+		return nil, validation.NewError("order", validation.CodeInvalidChoice, "asc desc", "must be one of: asc, desc")
+	}
+	if request.Cursor != "" {
+		cursor, err := repository_interface.DecodeChallengeCursor(request.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.Cursor = cursor
+	}
+	return params, nil
+}
+
+// SearchChallengesRequest параметры полнотекстового поиска
+type SearchChallengesRequest struct {
+	Query  string  `form:"q"`
+	Type   *string `form:"type"`
+	IsTeam *bool   `form:"is_team"`
+	Limit  int     `form:"limit"`
+	Offset int     `form:"offset"`
+}
+
+func bindSearchParams(c *gin.Context) (*repository_interface.ChallengeSearchParams, error) {
+	var request SearchChallengesRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		return nil, err
+	}
+	request.Query = strings.TrimSpace(request.Query)
+	return &repository_interface.ChallengeSearchParams{
+		Query:  request.Query,
+		Type:   request.Type,
+		IsTeam: request.IsTeam,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+var supportedLanguages = []string{entity.LanguageRu, entity.LanguageEn}
+
+// requestLanguage поддерживаемый язык с наибольшим весом q из Accept-Language,
+// при равных весах - указанный раньше. Без подходящего языка - entity.DefaultLanguage
+func requestLanguage(c *gin.Context) string {
+	language, best := entity.DefaultLanguage, 0.0
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		for _, supported := range supportedLanguages {
+			if primary == supported && weight > best {
+				language, best = supported, weight
+			}
+		}
+	}
+	return language
+}
+
+// respond пишет результат, подписи вызовов в котором переведены на язык запроса.
+// Для одного вызова в ответ добавляется его ETag
+func (h *ChallengesHandlers) respond(c *gin.Context, status int, result any) {
+	language := requestLanguage(c)
+	switch value := result.(type) {
+	case *entity.AuthenticationChallenge:
+		value.Localize(language)
+		c.Header("ETag", challengeETag(value.Version))
+	case []*entity.AuthenticationChallenge:
+		for _, challenge := range value {
+			challenge.Localize(language)
+		}
+	case *repository_interface.ChallengePage:
+		for _, challenge := range value.Items {
+			challenge.Localize(language)
+		}
+	case *repository_interface.ChallengeSearchPage:
+		for _, item := range value.Items {
+			if item.Challenge != nil {
+				item.Challenge.Localize(language)
+			}
+		}
+	}
+	c.Header("Content-Language", language)
+	c.Header("Vary", "Accept-Language")
+	c.JSON(status, result)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"challenge-service/internal/domain/challenge/delievery/http/problem"
+	"challenge-service/internal/infrastructure/lib/imaging"
+	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// uploadedFiles URL файлов, загруженных в рамках одного запроса
+type uploadedFiles []string
+
+// bindMultipartJSON читает данные запроса из JSON в поле формы field для multipart запросов
+// и из тела запроса для application/json
+func bindMultipartJSON(c *gin.Context, field string, obj any) error {
+	if !strings.HasPrefix(c.ContentType(), gin.MIMEMultipartPOSTForm) {
+		return c.ShouldBindJSON(obj)
+	}
+	data, ok := c.GetPostForm(field)
+	if !ok {
+		return fmt.Errorf("form field %q is required", field)
+	}
+	return json.Unmarshal([]byte(data), obj)
+}
+
+// uploadImage проверяет изображение из поля формы, сохраняет в хранилище перекодированный исходник
+// и его варианты. Если поле не передано, возвращается http.ErrMissingFile
+func (h *ChallengesHandlers) uploadImage(c *gin.Context, field string, variants []imaging.Variant,
+	uploaded *uploadedFiles) (string, map[string]string, error) {
+	file, _, err := c.Request.FormFile(field)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, h.images.MaxSize()+1))
+	if err != nil {
+		return "", nil, err
+	}
+	ctx := c.Request.Context()
+	_, span := tracing.Tracer().Start(ctx, "images.process", trace.WithAttributes(
+		attribute.String("image.field", field), attribute.Int("image.size", len(data))))
+	processed, err := h.images.Process(data, variants)
+	span.End()
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := storage.NewObjectKey(storage.NamespaceChallenges, "")
+	url, err := h.storage.Put(ctx, key+processed.Original.Ext, processed.Original.Data, processed.Original.ContentType)
+	if err != nil {
+		return "", nil, err
+	}
+	*uploaded = append(*uploaded, url)
+
+	variantURLs := make(map[string]string, len(processed.Variants))
+	for name, variant := range processed.Variants {
+		variantURL, err := h.storage.Put(ctx, key+"_"+name+variant.Ext, variant.Data, variant.ContentType)
+		if err != nil {
+			return "", nil, err
+		}
+		*uploaded = append(*uploaded, variantURL)
+		variantURLs[name] = variantURL
+	}
+	return url, variantURLs, nil
+}
+
+// writeUploadError отвечает на ошибку загрузки изображения из поля field
+func (h *ChallengesHandlers) writeUploadError(c *gin.Context, field string, err error) {
+	message := fmt.Sprintf("invalid %s: %s", field, err)
+	switch {
+	case errors.Is(err, http.ErrMissingFile):
+		problem.Write(c, problem.New(http.StatusBadRequest, field+"_required", field+" not provided"))
+	case errors.Is(err, imaging.ErrTooLarge):
+		problem.Write(c, problem.New(http.StatusRequestEntityTooLarge, "image_too_large", message))
+	case errors.Is(err, imaging.ErrUnsupportedType):
+		problem.Write(c, problem.New(http.StatusUnsupportedMediaType, "unsupported_image_type", message))
+	case errors.Is(err, imaging.ErrInvalidImage):
+		problem.Write(c, problem.New(http.StatusBadRequest, "invalid_image", message))
+	default:
+		h.logger(c).Error("Error uploading "+field+":", log.Err(err))
+		problem.Write(c, problem.New(http.StatusBadGateway, "storage_unavailable", "failed to store "+field))
+	}
+}
+
+// discardUploads удаляет загруженные файлы, если команда не была выполнена
+func (h *ChallengesHandlers) discardUploads(ctx context.Context, uploaded uploadedFiles) {
+	for _, url := range uploaded {
+		key, ok := h.storage.KeyFromURL(url)
+		if !ok {
+			continue
+		}
+		if err := h.storage.Delete(context.WithoutCancel(ctx), key); err != nil && !errors.Is(err, storage.ErrNotSupported) {
+			log.FromContext(ctx, h.log).Warn("failed to delete orphaned upload", slog.String("url", url), log.Err(err))
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"challenge-service/config"
+	"challenge-service/internal/infrastructure/lib/imaging"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"github.com/gin-gonic/gin"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestUploadedImageIsServedByStoredURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	localStorage, err := storage.NewLocalStorage(config.LocalStorageConfig{
+		Dir:            t.TempDir(),
+		PublicURL:      "http://localhost/media",
+		PublicPrefixes: []string{"challenges/"},
+	}, []byte("secret"), logger)
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	h := NewChallengesHandlers(&config.Config{}, logger, nil, nil, localStorage, imaging.NewProcessor(config.ImagesConfig{
+		MaxSize:      1 << 20,
+		MaxPixels:    1 << 20,
+		MaxDimension: 256,
+		AllowedTypes: []string{"image/png"},
+		JPEGQuality:  85,
+	}))
+
+	tests := []struct {
+		field    string
+		variants []imaging.Variant
+	}{
+		{field: "icon", variants: imaging.IconVariants},
+		{field: "image", variants: imaging.ImageVariants},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = multipartRequest(t, tt.field, testPNG(t))
+			var uploaded uploadedFiles
+			stored, variants, err := h.uploadImage(c, tt.field, tt.variants, &uploaded)
+			if err != nil {
+				t.Fatalf("uploadImage: %v", err)
+			}
+			if len(variants) != len(tt.variants) {
+				t.Fatalf("got %d variants, want %d", len(variants), len(tt.variants))
+			}
+
+			urls := []string{stored}
+			for _, variantURL := range variants {
+				urls = append(urls, variantURL)
+			}
+			for _, link := range urls {
+				parsed, err := url.Parse(link)
+				if err != nil {
+					t.Fatalf("parse %s: %v", link, err)
+				}
+				recorder := httptest.NewRecorder()
+				localStorage.Handler("/media/").ServeHTTP(recorder,
+					httptest.NewRequest(http.MethodGet, parsed.RequestURI(), nil))
+				if recorder.Code != http.StatusOK {
+					t.Errorf("GET %s = %d, want %d", link, recorder.Code, http.StatusOK)
+				}
+			}
+		})
+	}
+}
+
+// testPNG непрозрачное изображение 200x120
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 200, 120))
+	for y := 0; y < 120; y++ {
+		for x := 0; x < 200; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// multipartRequest запрос с файлом data в поле формы field
+func multipartRequest(t *testing.T, field string, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, field+".png")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close multipart writer: %v", err)
+	}
+	request := httptest.NewRequest(http.MethodPost, "/challenges", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
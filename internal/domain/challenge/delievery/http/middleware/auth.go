@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"challenge-service/internal/domain/challenge/delievery/http/problem"
+	"challenge-service/internal/infrastructure/lib/auth"
+	"challenge-service/internal/infrastructure/lib/log"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	PrincipalKey = "principal"
+	UserIDKey    = "user_id"
+)
+
+// Auth проверяет Bearer токен и кладет Principal в gin и request контексты,
+// логгер запроса дополняется user_id
+func Auth(verifier *auth.Verifier, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			problem.Write(c, problem.New(http.StatusUnauthorized, "token_required", "token is required"))
+			return
+		}
+
+		tokenString, found := strings.CutPrefix(authHeader, "Bearer ")
+		if !found || tokenString == "" {
+			problem.Write(c, problem.New(http.StatusUnauthorized, "invalid_authorization_header", "invalid authorization header"))
+			return
+		}
+
+		principal, err := verifier.Verify(c.Request.Context(), tokenString)
+		if err != nil {
+			log.FromContext(c.Request.Context(), logger).Warn("authentication failed", log.Err(err))
+			problem.Write(c, problem.New(http.StatusUnauthorized, "invalid_token", "invalid or expired token"))
+			return
+		}
+
+		setPrincipal(c, principal, logger)
+		c.Next()
+	}
+}
+
+// DevAuth выполняет все запросы от имени principal, когда проверка токенов выключена
+func DevAuth(principal *auth.Principal, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setPrincipal(c, principal, logger)
+		c.Next()
+	}
+}
+
+// setPrincipal кладет Principal в gin и request контексты, логгер запроса дополняется user_id
+func setPrincipal(c *gin.Context, principal *auth.Principal, logger *slog.Logger) {
+	c.Set(PrincipalKey, principal)
+	c.Set(UserIDKey, principal.UserID)
+	ctx := auth.WithPrincipal(c.Request.Context(), principal)
+	ctx = log.WithLogger(ctx, log.FromContext(ctx, logger).With(slog.Int64(UserIDKey, principal.UserID)))
+	c.Request = c.Request.WithContext(ctx)
+}
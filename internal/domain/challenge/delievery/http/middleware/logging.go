@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"challenge-service/internal/domain/challenge/delievery/http/problem"
+	"challenge-service/internal/infrastructure/lib/log"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	RequestIDKey    = "request_id"
+
+	// maxRequestIDLength ограничивает присланный клиентом идентификатор, более длинный заменяется своим
+	maxRequestIDLength = 128
+)
+
+// RequestID принимает X-Request-ID клиента или генерирует новый, возвращает его в ответе
+// и кладет в контекст запроса логгер с request_id, маршрутом и trace_id
+func RequestID(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		attrs := []any{
+			slog.String(RequestIDKey, requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("route", routeOf(c)),
+		}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			attrs = append(attrs, slog.String("trace_id", span.TraceID().String()))
+		}
+		ctx := log.WithLogger(c.Request.Context(), logger.With(attrs...))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AccessLog пишет строку журнала о каждом запросе через логгер запроса
+func AccessLog(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(started)),
+			slog.Int("size", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		log.FromContext(c.Request.Context(), logger).LogAttrs(c.Request.Context(), level, "request completed", attrs...)
+	}
+}
+
+// Recovery перехватывает панику обработчика и пишет ее со стеком в логгер запроса
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		log.FromContext(c.Request.Context(), logger).Error("panic recovered",
+			slog.Any("panic", recovered), slog.String("stack", string(debug.Stack())))
+		problem.Write(c, problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal server error"))
+	})
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"challenge-service/internal/infrastructure/metrics"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// unmatchedRoute метка запросов, не попавших ни в один маршрут
+const unmatchedRoute = "unmatched"
+
+// Metrics записывает длительность запроса с шаблоном маршрута и статусом ответа
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+		m.ObserveHTTP(routeOf(c), c.Request.Method, c.Writer.Status(), time.Since(started))
+	}
+}
+
+// routeOf шаблон маршрута запроса, чтобы не плодить метки и поля логов по путям
+func routeOf(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return unmatchedRoute
+}
@@ -0,0 +1,105 @@
+// Package problem ответы об ошибках в формате application/problem+json (RFC 7807).
+// Доменные ошибки переводятся в статус по их виду, код ошибки передается клиенту без изменений.
+package problem
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/lib/validation"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// Коды ошибок транспорта, коды доменных ошибок задаются в domainerr
+const (
+	CodeMalformedRequest = "malformed_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeValidationFailed = "validation_failed"
+	CodeRouteNotFound    = "route_not_found"
+	CodeInternal         = "internal_error"
+)
+
+// requestIDHeader заголовок, в который middleware.RequestID пишет ID запроса
+const requestIDHeader = "X-Request-ID"
+
+var kindStatuses = map[domainerr.Kind]int{
+	domainerr.KindNotFound:             http.StatusNotFound,
+	domainerr.KindConflict:             http.StatusConflict,
+	domainerr.KindForbidden:            http.StatusForbidden,
+	domainerr.KindValidation:           http.StatusUnprocessableEntity,
+	domainerr.KindInvalidState:         http.StatusConflict,
+	domainerr.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	domainerr.KindPreconditionRequired: http.StatusPreconditionRequired,
+}
+
+// Problem описание ошибки. Code - стабильный машиночитаемый код, RequestID связывает ответ с логами
+type Problem struct {
+	Type      string            `json:"type" example:"about:blank"`
+	Title     string            `json:"title" example:"Not Found"`
+	Status    int               `json:"status" example:"404"`
+	Detail    string            `json:"detail,omitempty" example:"challenge not found"`
+	Instance  string            `json:"instance,omitempty" example:"/challenges/42"`
+	Code      string            `json:"code" example:"challenge_not_found"`
+	RequestID string            `json:"request_id,omitempty"`
+	Fields    validation.Errors `json:"fields,omitempty"`
+}
+
+func New(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// FromError ответ на ошибку обработчика. Текст ошибок, не относящихся к предметной области
+// и проверке полей, клиенту не передается
+func FromError(err error) *Problem {
+	var fields validation.Errors
+	if errors.As(err, &fields) {
+		problem := New(http.StatusUnprocessableEntity, CodeValidationFailed, "request has invalid fields")
+		problem.Fields = fields
+		return problem
+	}
+	if domainErr, ok := domainerr.From(err); ok {
+		status, known := kindStatuses[domainErr.Kind]
+		if !known {
+			status = http.StatusBadRequest
+		}
+		return New(status, domainErr.Code, err.Error())
+	}
+	if errors.Is(err, cqrs.ErrValidation) {
+		return New(http.StatusUnprocessableEntity, CodeValidationFailed, "request is invalid")
+	}
+	return New(http.StatusInternalServerError, CodeInternal, "internal server error")
+}
+
+// Write пишет ответ и прерывает цепочку обработчиков
+func Write(c *gin.Context, problem *Problem) {
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.Writer.Header().Get(requestIDHeader)
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// Error отвечает на ошибку обработчика, ошибки сервера пишутся в логгер запроса
+func Error(c *gin.Context, logger *slog.Logger, err error) {
+	problem := FromError(err)
+	if problem.Status >= http.StatusInternalServerError {
+		log.FromContext(c.Request.Context(), logger).Error("request failed", log.Err(err))
+	}
+	Write(c, problem)
+}
+
+// BadRequest ответ на запрос, который не удалось разобрать. Причина пишется в лог, клиент получает detail
+func BadRequest(c *gin.Context, logger *slog.Logger, err error, detail string) {
+	log.FromContext(c.Request.Context(), logger).Warn("malformed request", log.Err(err))
+	Write(c, New(http.StatusBadRequest, CodeMalformedRequest, detail))
+}
@@ -0,0 +1,96 @@
+package problem
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/lib/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromError(t *testing.T) {
+	notFound := domainerr.NotFound("challenge_not_found", "challenge not found")
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantFields int
+	}{
+		{name: "not found", err: notFound,
+			wantStatus: http.StatusNotFound, wantCode: "challenge_not_found", wantDetail: "challenge not found"},
+		{name: "wrapped domain error keeps the wrapping text", err: fmt.Errorf("challenge 7: %w", notFound),
+			wantStatus: http.StatusNotFound, wantCode: "challenge_not_found", wantDetail: "challenge 7: challenge not found"},
+		{name: "conflict", err: domainerr.Conflict("already_registered", "already registered"),
+			wantStatus: http.StatusConflict, wantCode: "already_registered", wantDetail: "already registered"},
+		{name: "forbidden", err: domainerr.Forbidden("forbidden", "forbidden"),
+			wantStatus: http.StatusForbidden, wantCode: "forbidden", wantDetail: "forbidden"},
+		{name: "domain validation", err: domainerr.Validation("invalid_cursor", "invalid cursor"),
+			wantStatus: http.StatusUnprocessableEntity, wantCode: "invalid_cursor", wantDetail: "invalid cursor"},
+		{name: "invalid state", err: domainerr.InvalidState("invalid_challenge_state", "not allowed"),
+			wantStatus: http.StatusConflict, wantCode: "invalid_challenge_state", wantDetail: "not allowed"},
+		{name: "precondition failed", err: domainerr.PreconditionFailed("stale_version", "stale"),
+			wantStatus: http.StatusPreconditionFailed, wantCode: "stale_version", wantDetail: "stale"},
+		{name: "precondition required", err: domainerr.PreconditionRequired("version_required", "version required"),
+			wantStatus: http.StatusPreconditionRequired, wantCode: "version_required", wantDetail: "version required"},
+		{name: "unknown kind", err: &domainerr.Error{Kind: "other", Code: "other", Message: "other"},
+			wantStatus: http.StatusBadRequest, wantCode: "other", wantDetail: "other"},
+		{name: "field errors",
+			err: fmt.Errorf("%w: %w", cqrs.ErrValidation,
+				validation.NewError("end_date", validation.CodeMustBeAfter, "start_date", "must be after start_date")),
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodeValidationFailed,
+			wantDetail: "request has invalid fields", wantFields: 1},
+		{name: "bare validation error", err: fmt.Errorf("%w: bad", cqrs.ErrValidation),
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodeValidationFailed, wantDetail: "request is invalid"},
+		{name: "internal error text is hidden", err: errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError, wantCode: CodeInternal, wantDetail: "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := FromError(tt.err)
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode || problem.Detail != tt.wantDetail {
+				t.Fatalf("problem = %d %s %q, want %d %s %q", problem.Status, problem.Code, problem.Detail,
+					tt.wantStatus, tt.wantCode, tt.wantDetail)
+			}
+			if problem.Title != http.StatusText(tt.wantStatus) {
+				t.Errorf("Title = %q", problem.Title)
+			}
+			if len(problem.Fields) != tt.wantFields {
+				t.Errorf("got %d fields, want %d", len(problem.Fields), tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/challenges/42", nil)
+	c.Writer.Header().Set(requestIDHeader, "req-1")
+
+	Write(c, New(http.StatusNotFound, "challenge_not_found", "challenge not found"))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != ContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if !c.IsAborted() {
+		t.Fatal("handler chain was not aborted")
+	}
+	var body Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Instance != "/challenges/42" || body.RequestID != "req-1" || body.Code != "challenge_not_found" {
+		t.Fatalf("body = %+v", body)
+	}
+}
@@ -4,88 +4,124 @@ import (
 	"challenge-service/config"
 	"challenge-service/docs"
 	"challenge-service/internal/domain/challenge/delievery/http/handlers"
+	"challenge-service/internal/domain/challenge/delievery/http/middleware"
+	"challenge-service/internal/domain/challenge/delievery/http/problem"
+	"challenge-service/internal/infrastructure/lib/auth"
+	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"challenge-service/internal/infrastructure/metrics"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
-	jwt "github.com/golang-jwt/jwt"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"log/slog"
-	"net/http"
-	"strings"
-	"time"
+	"net"
+	nethttp "net/http"
 )
 
 type HTTPServer struct {
 	cfg                *config.Config
 	log                *slog.Logger
 	challengesHandlers *handlers.ChallengesHandlers
+	healthHandlers     *handlers.HealthHandlers
+	verifier           *auth.Verifier
+	devPrincipal       *auth.Principal
+	storage            storage.Storage
+	metrics            *metrics.Metrics
+
+	server *nethttp.Server
+	failed chan error
 }
 
-func NewHTTPServer(cfg *config.Config, log *slog.Logger, challengeHandlers *handlers.ChallengesHandlers) *HTTPServer {
+func NewHTTPServer(cfg *config.Config, log *slog.Logger, challengeHandlers *handlers.ChallengesHandlers,
+	healthHandlers *handlers.HealthHandlers, verifier *auth.Verifier, devPrincipal *auth.Principal,
+	storage storage.Storage, metrics *metrics.Metrics) *HTTPServer {
 	return &HTTPServer{
 		cfg:                cfg,
 		log:                log,
 		challengesHandlers: challengeHandlers,
+		healthHandlers:     healthHandlers,
+		verifier:           verifier,
+		devPrincipal:       devPrincipal,
+		storage:            storage,
+		metrics:            metrics,
+		failed:             make(chan error, 1),
 	}
 }
 
-// AuthMiddleware - middleware для проверки авторизации
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Получить токен из заголовка Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is required"})
-			c.Abort()
-			return
-		}
-
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header"})
-			c.Abort()
-			return
-		}
-
-		// Проверить токен
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrInvalidKey
-			}
-			return cfg.SecretKey, nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
-			return
-		}
+func (h *HTTPServer) Name() string {
+	return "http"
+}
 
-		// Проверка истечения срока действия токена
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			if exp, ok := claims["exp"].(float64); ok && time.Unix(int64(exp), 0).Before(time.Now()) {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has expired"})
-				c.Abort()
-				return
-			}
-			c.Set("userID", claims["user_id"])
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
-			return
+// Start открывает порт и обслуживает запросы в отдельной горутине. Ошибка занятого порта
+// возвращается сразу, последующие ошибки сервера - через Failed
+func (h *HTTPServer) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", h.cfg.HTTP.Address)
+	if err != nil {
+		return err
+	}
+	h.server = &nethttp.Server{
+		Handler:           h.Router(),
+		ReadHeaderTimeout: h.cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       h.cfg.HTTP.ReadTimeout,
+		WriteTimeout:      h.cfg.HTTP.WriteTimeout,
+		IdleTimeout:       h.cfg.HTTP.IdleTimeout,
+	}
+	h.log.Info("HTTP server listening", slog.String("address", listener.Addr().String()))
+	go func() {
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			h.log.Error("HTTP server failed", log.Err(err))
+			h.failed <- err
 		}
+	}()
+	return nil
+}
 
-		c.Next()
+// Stop перестает принимать соединения и дожидается завершения текущих запросов
+func (h *HTTPServer) Stop(ctx context.Context) error {
+	if h.server == nil {
+		return nil
 	}
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	return h.server.Shutdown(ctx)
 }
 
-func (h *HTTPServer) Run() {
-	router := gin.Default()
+func (h *HTTPServer) Failed() <-chan error {
+	return h.failed
+}
 
-	router.Use(gin.Recovery())
+func (h *HTTPServer) Router() *gin.Engine {
+	router := gin.New()
+
+	if h.cfg.Tracing.Exporter != tracing.ExporterNone && h.cfg.Tracing.Exporter != "" {
+		router.Use(otelgin.Middleware(h.cfg.Tracing.ServiceName))
+	}
+	// Recovery стоит после AccessLog, чтобы запрос с паникой тоже попал в журнал со статусом 500
+	router.Use(middleware.RequestID(h.log), middleware.AccessLog(h.log), middleware.Recovery(h.log))
+	if h.metrics != nil {
+		router.Use(middleware.Metrics(h.metrics))
+		router.GET(h.cfg.Metrics.Path, gin.WrapH(h.metrics.Handler()))
+	}
+	router.NoRoute(func(c *gin.Context) {
+		problem.Write(c, problem.New(nethttp.StatusNotFound, problem.CodeRouteNotFound, "route not found"))
+	})
 	router.GET("/pingpong", h.challengesHandlers.Ping)
+	router.GET("/healthz", h.healthHandlers.Healthz)
+	router.GET("/readyz", h.healthHandlers.Readyz)
+	if localStorage, ok := h.storage.(*storage.LocalStorage); ok {
+		router.GET("/media/*key", gin.WrapH(localStorage.Handler("/media/")))
+	}
 
 	api := router.Group("/")
-	//api.Use(AuthMiddleware(h.cfg))
+	if h.cfg.Auth.Enabled {
+		api.Use(middleware.Auth(h.verifier, h.log))
+	} else {
+		api.Use(middleware.DevAuth(h.devPrincipal, h.log))
+	}
 
 	challenges := api.Group("/")
 	{
@@ -93,6 +129,12 @@ func (h *HTTPServer) Run() {
 
 		challenges.GET("/challenges", h.challengesHandlers.GetAllChallenges)
 
+		challenges.GET("/challenges/search", h.challengesHandlers.SearchChallenges)
+
+		challenges.GET("/challenges/trash", h.challengesHandlers.GetTrash)
+
+		challenges.GET("/challenges/:id", h.challengesHandlers.GetChallenge)
+
 		challenges.PUT("/challenges/:id", h.challengesHandlers.UpdateChallenge)
 
 		challenges.DELETE("/challenges/:id", h.challengesHandlers.DeleteChallenge)
@@ -106,12 +148,20 @@ func (h *HTTPServer) Run() {
 		challenges.POST("/challenges/team/register/:team_id", h.challengesHandlers.RegisterTeam)
 
 		challenges.POST("/challenges/close/:challenge_id", h.challengesHandlers.CloseChallenge)
+
+		challenges.POST("/challenges/:id/progress", h.challengesHandlers.RecordProgress)
+
+		challenges.GET("/challenges/:id/leaderboard", h.challengesHandlers.GetLeaderboard)
+
+		challenges.POST("/challenges/:id/publish", h.challengesHandlers.PublishChallenge)
+
+		challenges.POST("/challenges/:id/cancel", h.challengesHandlers.CancelChallenge)
+
+		challenges.POST("/challenges/:id/archive", h.challengesHandlers.ArchiveChallenge)
+
+		challenges.POST("/challenges/:id/restore", h.challengesHandlers.RestoreChallenge)
 	}
 	docs.SwaggerInfo.BasePath = "/"
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	err := router.Run(":8004")
-	if err != nil {
-		h.log.Error("Failed to run server:", err)
-		panic(err)
-	}
+	return router
 }
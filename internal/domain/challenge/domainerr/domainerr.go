@@ -0,0 +1,70 @@
+// Package domainerr типизированные ошибки предметной области. Kind задает класс ошибки,
+// по которому транспорт выбирает статус ответа, Code - стабильный машиночитаемый код для клиентов.
+package domainerr
+
+import (
+	"errors"
+)
+
+type Kind string
+
+const (
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindForbidden    Kind = "forbidden"
+	KindValidation   Kind = "validation"
+	KindInvalidState Kind = "invalid_state"
+	// KindPreconditionFailed изменение основано на устаревшей версии объекта
+	KindPreconditionFailed Kind = "precondition_failed"
+	// KindPreconditionRequired изменение не указывает, на какой версии объекта оно основано
+	KindPreconditionRequired Kind = "precondition_required"
+)
+
+// Error ошибка предметной области. Текст ошибки и всех оборачивающих ее доменных ошибок
+// можно показывать клиенту, поэтому в него не попадают детали хранилища
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NotFound(code string, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Conflict(code string, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Forbidden(code string, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func Validation(code string, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+func InvalidState(code string, message string) *Error {
+	return &Error{Kind: KindInvalidState, Code: code, Message: message}
+}
+
+func PreconditionFailed(code string, message string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Message: message}
+}
+
+func PreconditionRequired(code string, message string) *Error {
+	return &Error{Kind: KindPreconditionRequired, Code: code, Message: message}
+}
+
+// From доменная ошибка из цепочки err
+func From(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
@@ -1,34 +1,87 @@
 package entity
 
 import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"gorm.io/gorm"
 	"time"
 )
 
 type AuthenticationChallenge struct {
-	ID          int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
-	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
-	Icon        string    `gorm:"type:varchar(255);not null" json:"icon"`
-	Image       string    `gorm:"type:varchar(255);not null" json:"image"`
-	Description string    `gorm:"type:text;not null" json:"description"`
-	StartDate   time.Time `gorm:"type:timestamptz;not null" json:"start_date"`
-	EndDate     time.Time `gorm:"type:timestamptz;not null" json:"end_date"`
-	Type        string    `gorm:"type:varchar(10);not null" json:"type"` // семейный, личный, общий(групповой)
-	IsTeam      bool      `gorm:"not null" json:"is_team"`
-	IsFinished  bool      `gorm:"not null" json:"is_finished"`
-	CreatorID   int64     `gorm:"not null" json:"creator_id"`
+	ID          int64           `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	Name        string          `gorm:"type:varchar(255);not null" json:"name"`
+	Icon        string          `gorm:"type:varchar(255);not null" json:"icon"`
+	Image       string          `gorm:"type:varchar(255);not null" json:"image"`
+	Description string          `gorm:"type:text;not null" json:"description"`
+	StartDate   time.Time       `gorm:"type:timestamptz;not null" json:"start_date"`
+	EndDate     time.Time       `gorm:"type:timestamptz;not null" json:"end_date"`
+	Type        ChallengeKind   `gorm:"type:varchar(16);not null" json:"type"`
+	IsTeam      bool            `gorm:"not null" json:"is_team"`
+	IsFinished  bool            `gorm:"not null" json:"is_finished"`
+	Status      ChallengeStatus `gorm:"type:varchar(16);not null;default:draft" json:"status"`
+	CreatorID   int64           `gorm:"not null" json:"creator_id"`
+	Version     int64           `gorm:"not null;default:1" json:"version"` // растет при каждом изменении, из нее строится ETag
+	// DeletedAt время перемещения в корзину. Удаленные вызовы не попадают в выборки репозитория
+	DeletedAt gorm.DeletedAt `json:"deleted_at" swaggertype:"string" format:"date-time"`
+
+	CoOrganizerIDs Int64List     `gorm:"type:jsonb;not null;default:'[]'" json:"co_organizer_ids"`
+	IconVariants   ImageVariants `gorm:"type:jsonb;not null;default:'{}'" json:"icon_variants"`
+	ImageVariants  ImageVariants `gorm:"type:jsonb;not null;default:'{}'" json:"image_variants"`
+
+	// ParticipantCount заполняется только в выдаче списков
+	ParticipantCount int64 `gorm:"->;-:migration" json:"participant_count"`
+	// TypeLabel подпись вида на языке запроса, заполняется транспортом через Localize
+	TypeLabel string `gorm:"-" json:"type_label,omitempty"`
 }
 
 func (AuthenticationChallenge) TableName() string {
 	return "authentication_challenge"
 }
 
+const (
+	ParticipantStatusActive    = "active"
+	ParticipantStatusCompleted = "completed"
+	ParticipantStatusFailed    = "failed"
+)
+
+var (
+	ErrChallengeNotFound   = domainerr.NotFound("challenge_not_found", "challenge not found")
+	ErrStaleVersion        = domainerr.PreconditionFailed("stale_version", "challenge was modified by another request")
+	ErrVersionRequired     = domainerr.PreconditionRequired("version_required", "update must specify the challenge version it is based on")
+	ErrParticipantNotFound = domainerr.NotFound("participant_not_found", "participant not found")
+	ErrAlreadyRegistered   = domainerr.Conflict("already_registered", "participant is already registered on the challenge")
+	ErrTeamRequired        = domainerr.Validation("team_required", "team challenge requires a team")
+	ErrTeamNotAllowed      = domainerr.Validation("team_not_allowed", "personal challenge does not accept teams")
+)
+
 type AuthenticationParticipant struct {
 	ID          int64                   `gorm:"primaryKey;autoIncrement:true" json:"id"`
 	Status      string                  `gorm:"type:varchar(10);not null" json:"status"`
-	Progress    string                  `gorm:"type:jsonb;not null" json:"progress"`
+	Progress    ParticipantProgress     `gorm:"type:jsonb;not null" json:"progress"`
 	Achievement string                  `gorm:"type:text;not null" json:"achievement"`
 	ChallengeID int64                   `gorm:"not null" json:"challenge_id"`
-	Challenge   AuthenticationChallenge `gorm:"foreignKey:ChallengeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Challenge   AuthenticationChallenge `gorm:"foreignKey:ChallengeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	UserID      int64                   `gorm:"not null" json:"creator_id"`
 	TeamID      int64                   `gorm:"not null" json:"team_id"`
 }
+
+func (AuthenticationParticipant) TableName() string {
+	return "authentication_participant"
+}
+
+// DurationDays количество календарных дней, которые длится вызов
+func (c AuthenticationChallenge) DurationDays() int {
+	if c.EndDate.Before(c.StartDate) {
+		return 0
+	}
+	start := c.StartDate.UTC().Truncate(24 * time.Hour)
+	end := c.EndDate.UTC().Truncate(24 * time.Hour)
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// Outcome итоговый статус участника после завершения вызова: выполнен, если отмечены все дни вызова
+func (p AuthenticationParticipant) Outcome(challenge AuthenticationChallenge) string {
+	if p.Progress.DaysDone >= challenge.DurationDays() {
+		return ParticipantStatusCompleted
+	}
+	return ParticipantStatusFailed
+}
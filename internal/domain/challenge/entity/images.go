@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ImageVariants URL вариантов изображения по имени варианта (например, "64", "card"), хранится в jsonb колонке
+type ImageVariants map[string]string
+
+func (v ImageVariants) Value() (driver.Value, error) {
+	if v == nil {
+		v = ImageVariants{}
+	}
+	return json.Marshal(map[string]string(v))
+}
+
+func (v *ImageVariants) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*v = ImageVariants{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, (*map[string]string)(v))
+	case string:
+		return json.Unmarshal([]byte(data), (*map[string]string)(v))
+	}
+	return fmt.Errorf("unsupported image variants value type %T", value)
+}
+
+// ImageURLs ссылки на иконку, изображение и все их варианты, пустые ссылки пропускаются
+func (c *AuthenticationChallenge) ImageURLs() []string {
+	urls := make([]string, 0, 2+len(c.IconVariants)+len(c.ImageVariants))
+	for _, url := range []string{c.Icon, c.Image} {
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+	for _, variants := range []ImageVariants{c.IconVariants, c.ImageVariants} {
+		for _, url := range variants {
+			if url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
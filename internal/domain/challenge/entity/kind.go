@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+)
+
+// ChallengeKind вид вызова. В базе и API хранится стабильным кодом, подпись для клиента
+// выбирается по языку запроса
+type ChallengeKind string
+
+const (
+	ChallengeKindFamily   ChallengeKind = "family"
+	ChallengeKindPersonal ChallengeKind = "personal"
+	ChallengeKindGroup    ChallengeKind = "group"
+)
+
+const (
+	LanguageRu = "ru"
+	LanguageEn = "en"
+
+	DefaultLanguage = LanguageRu
+)
+
+var (
+	ErrFamilyGroupRequired = domainerr.Validation("family_group_required",
+		"family challenge must be a team challenge: families take part as a family group")
+	ErrPersonalTeam = domainerr.Validation("personal_challenge_team",
+		"personal challenge cannot be a team challenge")
+)
+
+var challengeKindLabels = map[ChallengeKind]map[string]string{
+	ChallengeKindFamily:   {LanguageRu: "семейный", LanguageEn: "family"},
+	ChallengeKindPersonal: {LanguageRu: "личный", LanguageEn: "personal"},
+	ChallengeKindGroup:    {LanguageRu: "общий", LanguageEn: "group"},
+}
+
+func (k ChallengeKind) Valid() bool {
+	_, ok := challengeKindLabels[k]
+	return ok
+}
+
+// Label подпись вида на языке language, для неизвестного языка - на DefaultLanguage
+func (k ChallengeKind) Label(language string) string {
+	labels, ok := challengeKindLabels[k]
+	if !ok {
+		return string(k)
+	}
+	if label, ok := labels[language]; ok {
+		return label
+	}
+	return labels[DefaultLanguage]
+}
+
+// CheckKindRules проверяет правила вида: семейный вызов проходят семейной группой (командой),
+// личный - только поодиночке
+func (c *AuthenticationChallenge) CheckKindRules() error {
+	switch {
+	case c.Type == ChallengeKindFamily && !c.IsTeam:
+		return ErrFamilyGroupRequired
+	case c.Type == ChallengeKindPersonal && c.IsTeam:
+		return ErrPersonalTeam
+	}
+	return nil
+}
+
+// Localize заполняет подписи для языка language
+func (c *AuthenticationChallenge) Localize(language string) {
+	c.TypeLabel = c.Type.Label(language)
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+)
+
+type LeaderboardEntry struct {
+	Rank        int        `json:"rank"`
+	UserID      int64      `json:"user_id,omitempty"`
+	TeamID      int64      `json:"team_id,omitempty"`
+	Score       float64    `json:"score"`
+	Unit        string     `json:"unit,omitempty"`
+	DaysDone    int        `json:"days_done"`
+	LastCheckIn *time.Time `json:"last_check_in,omitempty"`
+}
+
+// Leaderboard участники вызова по местам: в командных вызовах ранжируются команды, в личных - пользователи.
+// Выше тот, у кого больше очков, при равенстве - больше дней с отметками, затем тот,
+// кто раньше сделал последнюю отметку, затем меньший ID. Места считает репозиторий
+type Leaderboard struct {
+	ChallengeID  int64              `json:"challenge_id"`
+	IsTeam       bool               `json:"is_team"`
+	Participants int                `json:"participants"`
+	Entries      []LeaderboardEntry `json:"entries"`
+	Me           *LeaderboardEntry  `json:"me,omitempty"`
+	NextOffset   *int               `json:"next_offset,omitempty"` // offset следующей страницы, если она есть
+}
+
+// NewLeaderboard собирает страницу таблицы из ранжированных строк. ranked содержит места
+// с offset+1 по offset+limit и может содержать строку субъекта me (пользователя или команды) вне страницы
+func NewLeaderboard(challenge AuthenticationChallenge, ranked []LeaderboardEntry, participants int,
+	offset int, limit int, me int64) *Leaderboard {
+	leaderboard := &Leaderboard{
+		ChallengeID:  challenge.ID,
+		IsTeam:       challenge.IsTeam,
+		Participants: participants,
+		Entries:      make([]LeaderboardEntry, 0, min(len(ranked), limit)),
+	}
+	for i := range ranked {
+		entry := ranked[i]
+		if me != 0 && entry.subjectID() == me {
+			leaderboard.Me = &entry
+		}
+		if entry.Rank > offset && entry.Rank <= offset+limit {
+			leaderboard.Entries = append(leaderboard.Entries, entry)
+		}
+	}
+	if next := offset + limit; next < participants {
+		leaderboard.NextOffset = &next
+	}
+	return leaderboard
+}
+
+func (e LeaderboardEntry) subjectID() int64 {
+	if e.TeamID != 0 {
+		return e.TeamID
+	}
+	return e.UserID
+}
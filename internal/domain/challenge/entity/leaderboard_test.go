@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"testing"
+)
+
+func TestNewLeaderboard(t *testing.T) {
+	users := func(ranks ...int) []LeaderboardEntry {
+		entries := make([]LeaderboardEntry, 0, len(ranks))
+		for _, rank := range ranks {
+			entries = append(entries, LeaderboardEntry{Rank: rank, UserID: int64(100 + rank)})
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name         string
+		challenge    AuthenticationChallenge
+		ranked       []LeaderboardEntry
+		participants int
+		offset       int
+		limit        int
+		me           int64
+		wantRanks    []int
+		wantMeRank   int
+		wantNext     int
+	}{
+		{
+			name:         "first page with more pages",
+			ranked:       users(1, 2),
+			participants: 5,
+			limit:        2,
+			wantRanks:    []int{1, 2},
+			wantNext:     2,
+		},
+		{
+			name:         "last page",
+			ranked:       users(5),
+			participants: 5,
+			offset:       4,
+			limit:        2,
+			wantRanks:    []int{5},
+		},
+		{
+			name:         "me on the page",
+			ranked:       users(1, 2, 3),
+			participants: 3,
+			limit:        3,
+			me:           102,
+			wantRanks:    []int{1, 2, 3},
+			wantMeRank:   2,
+		},
+		{
+			name:         "me outside of the page is not listed",
+			ranked:       users(3, 4, 9),
+			participants: 10,
+			offset:       2,
+			limit:        2,
+			me:           109,
+			wantRanks:    []int{3, 4},
+			wantMeRank:   9,
+			wantNext:     4,
+		},
+		{
+			name:      "team challenge matches me by team",
+			challenge: AuthenticationChallenge{IsTeam: true},
+			ranked: []LeaderboardEntry{
+				{Rank: 1, TeamID: 7},
+				{Rank: 2, TeamID: 8},
+			},
+			participants: 2,
+			limit:        10,
+			me:           8,
+			wantRanks:    []int{1, 2},
+			wantMeRank:   2,
+		},
+		{
+			name:         "no participants",
+			participants: 0,
+			limit:        10,
+			me:           101,
+			wantRanks:    []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaderboard := NewLeaderboard(tt.challenge, tt.ranked, tt.participants, tt.offset, tt.limit, tt.me)
+			if leaderboard.IsTeam != tt.challenge.IsTeam || leaderboard.Participants != tt.participants {
+				t.Errorf("IsTeam, Participants = %v, %d", leaderboard.IsTeam, leaderboard.Participants)
+			}
+			if leaderboard.Entries == nil {
+				t.Fatal("Entries is nil, want empty slice")
+			}
+			if len(leaderboard.Entries) != len(tt.wantRanks) {
+				t.Fatalf("got %d entries, want %d", len(leaderboard.Entries), len(tt.wantRanks))
+			}
+			for i, rank := range tt.wantRanks {
+				if leaderboard.Entries[i].Rank != rank {
+					t.Errorf("Entries[%d].Rank = %d, want %d", i, leaderboard.Entries[i].Rank, rank)
+				}
+			}
+			switch {
+			case tt.wantMeRank == 0 && leaderboard.Me != nil:
+				t.Errorf("Me = %+v, want nil", leaderboard.Me)
+			case tt.wantMeRank != 0 && (leaderboard.Me == nil || leaderboard.Me.Rank != tt.wantMeRank):
+				t.Errorf("Me = %+v, want rank %d", leaderboard.Me, tt.wantMeRank)
+			}
+			switch {
+			case tt.wantNext == 0 && leaderboard.NextOffset != nil:
+				t.Errorf("NextOffset = %d, want nil", *leaderboard.NextOffset)
+			case tt.wantNext != 0 && (leaderboard.NextOffset == nil || *leaderboard.NextOffset != tt.wantNext):
+				t.Errorf("NextOffset = %v, want %d", leaderboard.NextOffset, tt.wantNext)
+			}
+		})
+	}
+}
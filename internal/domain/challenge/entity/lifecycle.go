@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"fmt"
+	"time"
+)
+
+type ChallengeStatus string
+
+const (
+	ChallengeStatusDraft     ChallengeStatus = "draft"
+	ChallengeStatusScheduled ChallengeStatus = "scheduled"
+	ChallengeStatusActive    ChallengeStatus = "active"
+	ChallengeStatusFinished  ChallengeStatus = "finished"
+	ChallengeStatusArchived  ChallengeStatus = "archived"
+	ChallengeStatusCancelled ChallengeStatus = "cancelled"
+)
+
+// challengeTransitions допустимые переходы жизненного цикла вызова
+var challengeTransitions = map[ChallengeStatus][]ChallengeStatus{
+	ChallengeStatusDraft:     {ChallengeStatusScheduled, ChallengeStatusActive, ChallengeStatusCancelled},
+	ChallengeStatusScheduled: {ChallengeStatusActive, ChallengeStatusCancelled},
+	ChallengeStatusActive:    {ChallengeStatusFinished, ChallengeStatusCancelled},
+	ChallengeStatusFinished:  {ChallengeStatusArchived},
+	ChallengeStatusCancelled: {ChallengeStatusArchived},
+}
+
+var ErrChallengeState = domainerr.InvalidState("invalid_challenge_state", "operation is not allowed in the current challenge state")
+
+// TransitionError недопустимый переход между состояниями
+type TransitionError struct {
+	From ChallengeStatus
+	To   ChallengeStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("illegal challenge transition from %q to %q", e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrChallengeState
+}
+
+// StateError операция недоступна в текущем состоянии вызова
+type StateError struct {
+	Status    ChallengeStatus
+	Operation string
+}
+
+func (e *StateError) Error() string {
+	return fmt.Sprintf("cannot %s a challenge in %q state", e.Operation, e.Status)
+}
+
+func (e *StateError) Unwrap() error {
+	return ErrChallengeState
+}
+
+func (s ChallengeStatus) CanTransitionTo(target ChallengeStatus) bool {
+	for _, allowed := range challengeTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo переводит вызов в новое состояние, если переход разрешен
+func (c *AuthenticationChallenge) TransitionTo(target ChallengeStatus) error {
+	if !c.Status.CanTransitionTo(target) {
+		return &TransitionError{From: c.Status, To: target}
+	}
+	c.Status = target
+	c.IsFinished = target == ChallengeStatusFinished
+	return nil
+}
+
+// Publish выводит вызов из черновика: в scheduled, если он еще не начался, иначе сразу в active
+func (c *AuthenticationChallenge) Publish(now time.Time) error {
+	if c.Status != ChallengeStatusDraft {
+		return &TransitionError{From: c.Status, To: ChallengeStatusScheduled}
+	}
+	if now.Before(c.StartDate) {
+		return c.TransitionTo(ChallengeStatusScheduled)
+	}
+	return c.TransitionTo(ChallengeStatusActive)
+}
+
+// DueToStart вызов запланирован и его дата начала наступила
+func (c *AuthenticationChallenge) DueToStart(now time.Time) bool {
+	return c.Status == ChallengeStatusScheduled && !now.Before(c.StartDate)
+}
+
+// DueToFinish вызов идет и его дата окончания прошла
+func (c *AuthenticationChallenge) DueToFinish(now time.Time) bool {
+	return c.Status == ChallengeStatusActive && now.After(c.EndDate)
+}
+
+func (c *AuthenticationChallenge) Start() error {
+	return c.TransitionTo(ChallengeStatusActive)
+}
+
+func (c *AuthenticationChallenge) Finish() error {
+	return c.TransitionTo(ChallengeStatusFinished)
+}
+
+func (c *AuthenticationChallenge) Cancel() error {
+	return c.TransitionTo(ChallengeStatusCancelled)
+}
+
+func (c *AuthenticationChallenge) Archive() error {
+	if err := c.TransitionTo(ChallengeStatusArchived); err != nil {
+		return err
+	}
+	c.IsFinished = true
+	return nil
+}
+
+func (c *AuthenticationChallenge) EnsureEditable() error {
+	switch c.Status {
+	case ChallengeStatusDraft, ChallengeStatusScheduled, ChallengeStatusActive:
+		return nil
+	}
+	return &StateError{Status: c.Status, Operation: "edit"}
+}
+
+func (c *AuthenticationChallenge) EnsureAcceptsRegistrations() error {
+	switch c.Status {
+	case ChallengeStatusScheduled, ChallengeStatusActive:
+		return nil
+	}
+	return &StateError{Status: c.Status, Operation: "register on"}
+}
+
+func (c *AuthenticationChallenge) EnsureAcceptsProgress() error {
+	if c.Status == ChallengeStatusActive {
+		return nil
+	}
+	return &StateError{Status: c.Status, Operation: "record progress on"}
+}
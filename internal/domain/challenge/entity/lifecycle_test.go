@@ -0,0 +1,192 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var allStatuses = []ChallengeStatus{
+	ChallengeStatusDraft,
+	ChallengeStatusScheduled,
+	ChallengeStatusActive,
+	ChallengeStatusFinished,
+	ChallengeStatusArchived,
+	ChallengeStatusCancelled,
+}
+
+func TestChallengeStatusCanTransitionTo(t *testing.T) {
+	allowed := map[[2]ChallengeStatus]bool{
+		{ChallengeStatusDraft, ChallengeStatusScheduled}:     true,
+		{ChallengeStatusDraft, ChallengeStatusActive}:        true,
+		{ChallengeStatusDraft, ChallengeStatusCancelled}:     true,
+		{ChallengeStatusScheduled, ChallengeStatusActive}:    true,
+		{ChallengeStatusScheduled, ChallengeStatusCancelled}: true,
+		{ChallengeStatusActive, ChallengeStatusFinished}:     true,
+		{ChallengeStatusActive, ChallengeStatusCancelled}:    true,
+		{ChallengeStatusFinished, ChallengeStatusArchived}:   true,
+		{ChallengeStatusCancelled, ChallengeStatusArchived}:  true,
+	}
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			want := allowed[[2]ChallengeStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestChallengeTransitionTo(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         ChallengeStatus
+		apply        func(c *AuthenticationChallenge) error
+		wantStatus   ChallengeStatus
+		wantFinished bool
+		wantErr      bool
+	}{
+		{name: "start scheduled", from: ChallengeStatusScheduled,
+			apply: (*AuthenticationChallenge).Start, wantStatus: ChallengeStatusActive},
+		{name: "finish active", from: ChallengeStatusActive,
+			apply: (*AuthenticationChallenge).Finish, wantStatus: ChallengeStatusFinished, wantFinished: true},
+		{name: "cancel draft", from: ChallengeStatusDraft,
+			apply: (*AuthenticationChallenge).Cancel, wantStatus: ChallengeStatusCancelled},
+		{name: "archive finished", from: ChallengeStatusFinished,
+			apply: (*AuthenticationChallenge).Archive, wantStatus: ChallengeStatusArchived, wantFinished: true},
+		{name: "archive cancelled", from: ChallengeStatusCancelled,
+			apply: (*AuthenticationChallenge).Archive, wantStatus: ChallengeStatusArchived, wantFinished: true},
+		{name: "finish draft", from: ChallengeStatusDraft,
+			apply: (*AuthenticationChallenge).Finish, wantStatus: ChallengeStatusDraft, wantErr: true},
+		{name: "cancel finished", from: ChallengeStatusFinished,
+			apply: (*AuthenticationChallenge).Cancel, wantStatus: ChallengeStatusFinished, wantErr: true},
+		{name: "archive active", from: ChallengeStatusActive,
+			apply: (*AuthenticationChallenge).Archive, wantStatus: ChallengeStatusActive, wantErr: true},
+		{name: "start archived", from: ChallengeStatusArchived,
+			apply: (*AuthenticationChallenge).Start, wantStatus: ChallengeStatusArchived, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := &AuthenticationChallenge{Status: tt.from}
+			err := tt.apply(challenge)
+			if tt.wantErr {
+				var transitionErr *TransitionError
+				if !errors.As(err, &transitionErr) || !errors.Is(err, ErrChallengeState) {
+					t.Fatalf("err = %v, want TransitionError wrapping ErrChallengeState", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if challenge.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", challenge.Status, tt.wantStatus)
+			}
+			if challenge.IsFinished != tt.wantFinished {
+				t.Errorf("IsFinished = %v, want %v", challenge.IsFinished, tt.wantFinished)
+			}
+		})
+	}
+}
+
+func TestChallengePublish(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		status     ChallengeStatus
+		start      time.Time
+		wantStatus ChallengeStatus
+		wantErr    bool
+	}{
+		{name: "future start is scheduled", status: ChallengeStatusDraft,
+			start: now.Add(time.Hour), wantStatus: ChallengeStatusScheduled},
+		{name: "start now is active", status: ChallengeStatusDraft,
+			start: now, wantStatus: ChallengeStatusActive},
+		{name: "past start is active", status: ChallengeStatusDraft,
+			start: now.Add(-24 * time.Hour), wantStatus: ChallengeStatusActive},
+		{name: "already published", status: ChallengeStatusScheduled,
+			start: now.Add(time.Hour), wantStatus: ChallengeStatusScheduled, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := &AuthenticationChallenge{Status: tt.status, StartDate: tt.start}
+			err := challenge.Publish(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrChallengeState) {
+				t.Fatalf("err = %v, want ErrChallengeState", err)
+			}
+			if challenge.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", challenge.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestChallengeDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		status        ChallengeStatus
+		start, end    time.Time
+		wantDueStart  bool
+		wantDueFinish bool
+	}{
+		{name: "scheduled and started", status: ChallengeStatusScheduled,
+			start: now, end: now.Add(time.Hour), wantDueStart: true},
+		{name: "scheduled in the future", status: ChallengeStatusScheduled,
+			start: now.Add(time.Second), end: now.Add(time.Hour)},
+		{name: "active and ended", status: ChallengeStatusActive,
+			start: now.Add(-time.Hour), end: now.Add(-time.Second), wantDueFinish: true},
+		{name: "active ending now", status: ChallengeStatusActive,
+			start: now.Add(-time.Hour), end: now},
+		{name: "draft past dates", status: ChallengeStatusDraft,
+			start: now.Add(-2 * time.Hour), end: now.Add(-time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := &AuthenticationChallenge{Status: tt.status, StartDate: tt.start, EndDate: tt.end}
+			if got := challenge.DueToStart(now); got != tt.wantDueStart {
+				t.Errorf("DueToStart = %v, want %v", got, tt.wantDueStart)
+			}
+			if got := challenge.DueToFinish(now); got != tt.wantDueFinish {
+				t.Errorf("DueToFinish = %v, want %v", got, tt.wantDueFinish)
+			}
+		})
+	}
+}
+
+func TestChallengeStateGuards(t *testing.T) {
+	tests := []struct {
+		status            ChallengeStatus
+		editable          bool
+		acceptsRegistrant bool
+		acceptsProgress   bool
+	}{
+		{status: ChallengeStatusDraft, editable: true},
+		{status: ChallengeStatusScheduled, editable: true, acceptsRegistrant: true},
+		{status: ChallengeStatusActive, editable: true, acceptsRegistrant: true, acceptsProgress: true},
+		{status: ChallengeStatusFinished},
+		{status: ChallengeStatusArchived},
+		{status: ChallengeStatusCancelled},
+	}
+	check := func(t *testing.T, guard string, err error, want bool) {
+		t.Helper()
+		if want && err != nil {
+			t.Errorf("%s: unexpected error %v", guard, err)
+		}
+		if !want {
+			var stateErr *StateError
+			if !errors.As(err, &stateErr) || !errors.Is(err, ErrChallengeState) {
+				t.Errorf("%s: err = %v, want StateError wrapping ErrChallengeState", guard, err)
+			}
+		}
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			challenge := &AuthenticationChallenge{Status: tt.status}
+			check(t, "EnsureEditable", challenge.EnsureEditable(), tt.editable)
+			check(t, "EnsureAcceptsRegistrations", challenge.EnsureAcceptsRegistrations(), tt.acceptsRegistrant)
+			check(t, "EnsureAcceptsProgress", challenge.EnsureAcceptsProgress(), tt.acceptsProgress)
+		})
+	}
+}
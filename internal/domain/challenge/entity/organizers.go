@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Int64List список идентификаторов, хранится в jsonb колонке
+type Int64List []int64
+
+func (l Int64List) Value() (driver.Value, error) {
+	if l == nil {
+		l = Int64List{}
+	}
+	return json.Marshal([]int64(l))
+}
+
+func (l *Int64List) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = Int64List{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]int64)(l))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]int64)(l))
+	}
+	return fmt.Errorf("unsupported int64 list value type %T", value)
+}
+
+// IsOrganizer создатель вызова или один из его соорганизаторов
+func (c AuthenticationChallenge) IsOrganizer(userID int64) bool {
+	return c.CreatorID == userID || slices.Contains(c.CoOrganizerIDs, userID)
+}
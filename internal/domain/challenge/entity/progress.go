@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Отметку можно поставить задним числом не раньше CheckInBackdateLimit от текущего момента,
+// чтобы пропущенный день можно было догнать, но не заполнить весь вызов разом.
+// Отметки из будущего отклоняются с допуском CheckInClockSkew на расхождение часов клиента
+const (
+	CheckInBackdateLimit = 48 * time.Hour
+	CheckInClockSkew     = 5 * time.Minute
+)
+
+var (
+	ErrProgressUnitMismatch = domainerr.Validation("progress_unit_mismatch", "check-in unit does not match progress unit")
+	ErrCheckInOutOfRange    = domainerr.Validation("check_in_out_of_range", "check-in is outside of the challenge dates")
+	ErrCheckInInFuture      = domainerr.Validation("check_in_in_future", "check-in timestamp is in the future")
+	ErrCheckInTooOld        = domainerr.Validation("check_in_too_old", "check-in cannot be backdated by more than 48 hours")
+)
+
+// CheckIn одна отметка участника о прогрессе
+type CheckIn struct {
+	Value     float64   `json:"value"`
+	Unit      string    `json:"unit"`
+	Timestamp time.Time `json:"timestamp"`
+	Note      string    `json:"note,omitempty"`
+}
+
+// ParticipantProgress типизированный документ прогресса, хранится в jsonb колонке progress
+type ParticipantProgress struct {
+	CheckIns    []CheckIn  `json:"check_ins"`
+	Total       float64    `json:"total"`
+	Unit        string     `json:"unit,omitempty"`
+	DaysDone    int        `json:"days_done"`
+	DaysTotal   int        `json:"days_total"`
+	LastCheckIn *time.Time `json:"last_check_in,omitempty"`
+}
+
+func NewParticipantProgress(challenge AuthenticationChallenge) ParticipantProgress {
+	return ParticipantProgress{
+		CheckIns:  []CheckIn{},
+		DaysTotal: challenge.DurationDays(),
+	}
+}
+
+// AddCheckIn добавляет отметку и пересчитывает агрегаты документа. Отметка без времени ставится на now
+func (p *ParticipantProgress) AddCheckIn(checkIn CheckIn, challenge AuthenticationChallenge, now time.Time) error {
+	if p.Unit != "" && checkIn.Unit != "" && checkIn.Unit != p.Unit {
+		return fmt.Errorf("%w: progress is tracked in %q, got %q", ErrProgressUnitMismatch, p.Unit, checkIn.Unit)
+	}
+	if checkIn.Timestamp.IsZero() {
+		checkIn.Timestamp = now
+	}
+	checkIn.Timestamp = checkIn.Timestamp.UTC()
+	switch {
+	case checkIn.Timestamp.After(now.Add(CheckInClockSkew)):
+		return ErrCheckInInFuture
+	case checkIn.Timestamp.Before(now.Add(-CheckInBackdateLimit)):
+		return ErrCheckInTooOld
+	case checkIn.Timestamp.Before(challenge.StartDate) || checkIn.Timestamp.After(challenge.EndDate):
+		return ErrCheckInOutOfRange
+	}
+	if checkIn.Unit == "" {
+		checkIn.Unit = p.Unit
+	}
+
+	p.CheckIns = append(p.CheckIns, checkIn)
+	p.recalculate(challenge)
+	return nil
+}
+
+func (p *ParticipantProgress) recalculate(challenge AuthenticationChallenge) {
+	days := make(map[string]struct{})
+	p.Total = 0
+	p.LastCheckIn = nil
+	for i := range p.CheckIns {
+		checkIn := p.CheckIns[i]
+		p.Total += checkIn.Value
+		if p.Unit == "" {
+			p.Unit = checkIn.Unit
+		}
+		days[checkIn.Timestamp.Format(time.DateOnly)] = struct{}{}
+		if p.LastCheckIn == nil || checkIn.Timestamp.After(*p.LastCheckIn) {
+			p.LastCheckIn = &checkIn.Timestamp
+		}
+	}
+	p.DaysDone = len(days)
+	p.DaysTotal = challenge.DurationDays()
+}
+
+// Value сериализует документ в jsonb
+func (p ParticipantProgress) Value() (driver.Value, error) {
+	if p.CheckIns == nil {
+		p.CheckIns = []CheckIn{}
+	}
+	return json.Marshal(p)
+}
+
+// Scan читает документ из jsonb, пустое значение трактуется как пустой прогресс
+func (p *ParticipantProgress) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*p = ParticipantProgress{CheckIns: []CheckIn{}}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported progress value type %T", value)
+	}
+	if len(data) == 0 {
+		*p = ParticipantProgress{CheckIns: []CheckIn{}}
+		return nil
+	}
+	return json.Unmarshal(data, p)
+}
@@ -0,0 +1,183 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParticipantProgressAddCheckIn(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	challenge := AuthenticationChallenge{StartDate: start, EndDate: start.Add(10*24*time.Hour - time.Second)}
+	now := start.Add(5*24*time.Hour + 12*time.Hour)
+
+	tests := []struct {
+		name         string
+		now          time.Time // по умолчанию середина вызова
+		existing     []CheckIn
+		checkIn      CheckIn
+		wantErr      error
+		wantTotal    float64
+		wantUnit     string
+		wantDaysDone int
+		wantLast     time.Time
+	}{
+		{
+			name:         "first check-in sets the unit",
+			checkIn:      CheckIn{Value: 10, Unit: "km", Timestamp: now.Add(-time.Hour)},
+			wantTotal:    10,
+			wantUnit:     "km",
+			wantDaysDone: 1,
+			wantLast:     now.Add(-time.Hour),
+		},
+		{
+			name:         "missing timestamp is set to now",
+			checkIn:      CheckIn{Value: 1},
+			wantTotal:    1,
+			wantDaysDone: 1,
+			wantLast:     now,
+		},
+		{
+			name:         "same day is counted once",
+			existing:     []CheckIn{{Value: 2, Unit: "km", Timestamp: now.Add(-2 * time.Hour)}},
+			checkIn:      CheckIn{Value: 3, Timestamp: now.Add(-time.Hour)},
+			wantTotal:    5,
+			wantUnit:     "km",
+			wantDaysDone: 1,
+			wantLast:     now.Add(-time.Hour),
+		},
+		{
+			name:         "backdated check-in counts another day but keeps the latest",
+			existing:     []CheckIn{{Value: 2, Unit: "km", Timestamp: now.Add(-time.Hour)}},
+			checkIn:      CheckIn{Value: 3, Unit: "km", Timestamp: now.Add(-30 * time.Hour)},
+			wantTotal:    5,
+			wantUnit:     "km",
+			wantDaysDone: 2,
+			wantLast:     now.Add(-time.Hour),
+		},
+		{
+			name:         "within clock skew",
+			checkIn:      CheckIn{Value: 1, Timestamp: now.Add(CheckInClockSkew)},
+			wantTotal:    1,
+			wantDaysDone: 1,
+			wantLast:     now.Add(CheckInClockSkew),
+		},
+		{
+			name:     "unit mismatch",
+			existing: []CheckIn{{Value: 2, Unit: "km", Timestamp: now.Add(-time.Hour)}},
+			checkIn:  CheckIn{Value: 1, Unit: "min", Timestamp: now},
+			wantErr:  ErrProgressUnitMismatch,
+		},
+		{
+			name:    "future timestamp",
+			checkIn: CheckIn{Value: 1, Timestamp: now.Add(CheckInClockSkew + time.Second)},
+			wantErr: ErrCheckInInFuture,
+		},
+		{
+			name:    "backdated beyond the limit",
+			checkIn: CheckIn{Value: 1, Timestamp: now.Add(-CheckInBackdateLimit - time.Second)},
+			wantErr: ErrCheckInTooOld,
+		},
+		{
+			name:    "before the challenge start",
+			now:     start.Add(time.Hour),
+			checkIn: CheckIn{Value: 1, Timestamp: start.Add(-time.Second)},
+			wantErr: ErrCheckInOutOfRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := now
+			if !tt.now.IsZero() {
+				now = tt.now
+			}
+			progress := NewParticipantProgress(challenge)
+			for _, checkIn := range tt.existing {
+				if err := progress.AddCheckIn(checkIn, challenge, now); err != nil {
+					t.Fatalf("add existing check-in: %v", err)
+				}
+			}
+			before := len(progress.CheckIns)
+
+			err := progress.AddCheckIn(tt.checkIn, challenge, now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if len(progress.CheckIns) != before {
+					t.Fatalf("rejected check-in was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if progress.Total != tt.wantTotal {
+				t.Errorf("Total = %v, want %v", progress.Total, tt.wantTotal)
+			}
+			if progress.Unit != tt.wantUnit {
+				t.Errorf("Unit = %q, want %q", progress.Unit, tt.wantUnit)
+			}
+			if progress.DaysDone != tt.wantDaysDone {
+				t.Errorf("DaysDone = %d, want %d", progress.DaysDone, tt.wantDaysDone)
+			}
+			if progress.DaysTotal != 10 {
+				t.Errorf("DaysTotal = %d, want 10", progress.DaysTotal)
+			}
+			if progress.LastCheckIn == nil || !progress.LastCheckIn.Equal(tt.wantLast) {
+				t.Errorf("LastCheckIn = %v, want %v", progress.LastCheckIn, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestParticipantProgressScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		wantTotal float64
+		wantErr   bool
+	}{
+		{name: "nil", value: nil},
+		{name: "empty bytes", value: []byte{}},
+		{name: "bytes", value: []byte(`{"check_ins":[],"total":7}`), wantTotal: 7},
+		{name: "string", value: `{"check_ins":[],"total":3}`, wantTotal: 3},
+		{name: "unsupported type", value: 42, wantErr: true},
+		{name: "malformed json", value: []byte(`{`), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var progress ParticipantProgress
+			err := progress.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if progress.CheckIns == nil {
+				t.Errorf("CheckIns is nil, want empty slice")
+			}
+			if progress.Total != tt.wantTotal {
+				t.Errorf("Total = %v, want %v", progress.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestParticipantProgressValueRoundTrip(t *testing.T) {
+	value, err := ParticipantProgress{}.Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	if got := string(value.([]byte)); got != `{"check_ins":[],"total":0,"days_done":0,"days_total":0}` {
+		t.Fatalf("Value = %s", got)
+	}
+	var progress ParticipantProgress
+	if err := progress.Scan(value); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if progress.CheckIns == nil || len(progress.CheckIns) != 0 {
+		t.Fatalf("CheckIns = %#v, want empty slice", progress.CheckIns)
+	}
+}
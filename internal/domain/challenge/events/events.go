@@ -0,0 +1,181 @@
+// Package events доменные события вызовов, публикуемые обработчиками команд после сохранения изменений.
+package events
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/infrastructure/cqrs"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	ChallengeCreatedEvent      = "challenge.created"
+	ChallengeUpdatedEvent      = "challenge.updated"
+	ChallengeDeletedEvent      = "challenge.deleted"
+	ChallengeRestoredEvent     = "challenge.restored"
+	ParticipantRegisteredEvent = "challenge.participant_registered"
+	ChallengeClosedEvent       = "challenge.closed"
+	DaysLeftReminderEvent      = "challenge.days_left_reminder"
+)
+
+type ChallengeCreated struct {
+	cqrs.BaseEvent
+	Challenge entity.AuthenticationChallenge `json:"challenge"`
+}
+
+func NewChallengeCreated(challenge *entity.AuthenticationChallenge) *ChallengeCreated {
+	return &ChallengeCreated{
+		BaseEvent: cqrs.NewBaseEvent(challenge.ID),
+		Challenge: *challenge,
+	}
+}
+
+func (*ChallengeCreated) EventName() string {
+	return ChallengeCreatedEvent
+}
+
+// ChallengeUpdated изменены данные вызова или его состояние жизненного цикла
+type ChallengeUpdated struct {
+	cqrs.BaseEvent
+	Challenge entity.AuthenticationChallenge `json:"challenge"`
+}
+
+func NewChallengeUpdated(challenge *entity.AuthenticationChallenge) *ChallengeUpdated {
+	return &ChallengeUpdated{
+		BaseEvent: cqrs.NewBaseEvent(challenge.ID),
+		Challenge: *challenge,
+	}
+}
+
+func (*ChallengeUpdated) EventName() string {
+	return ChallengeUpdatedEvent
+}
+
+type ChallengeDeleted struct {
+	cqrs.BaseEvent
+	ChallengeID int64 `json:"challenge_id"`
+}
+
+func NewChallengeDeleted(challengeID int64) *ChallengeDeleted {
+	return &ChallengeDeleted{
+		BaseEvent:   cqrs.NewBaseEvent(challengeID),
+		ChallengeID: challengeID,
+	}
+}
+
+func (*ChallengeDeleted) EventName() string {
+	return ChallengeDeletedEvent
+}
+
+// ChallengeRestored вызов восстановлен из корзины
+type ChallengeRestored struct {
+	cqrs.BaseEvent
+	Challenge entity.AuthenticationChallenge `json:"challenge"`
+}
+
+func NewChallengeRestored(challenge *entity.AuthenticationChallenge) *ChallengeRestored {
+	return &ChallengeRestored{
+		BaseEvent: cqrs.NewBaseEvent(challenge.ID),
+		Challenge: *challenge,
+	}
+}
+
+func (*ChallengeRestored) EventName() string {
+	return ChallengeRestoredEvent
+}
+
+type ParticipantRegistered struct {
+	cqrs.BaseEvent
+	Challenge   entity.AuthenticationChallenge   `json:"challenge"`
+	Participant entity.AuthenticationParticipant `json:"participant"`
+}
+
+func NewParticipantRegistered(challenge *entity.AuthenticationChallenge,
+	participant *entity.AuthenticationParticipant) *ParticipantRegistered {
+	return &ParticipantRegistered{
+		BaseEvent:   cqrs.NewBaseEvent(challenge.ID),
+		Challenge:   *challenge,
+		Participant: *participant,
+	}
+}
+
+func (*ParticipantRegistered) EventName() string {
+	return ParticipantRegisteredEvent
+}
+
+// ChallengeClosed вызов завершен, Participants содержит итоговые статусы участников
+type ChallengeClosed struct {
+	cqrs.BaseEvent
+	Challenge    entity.AuthenticationChallenge     `json:"challenge"`
+	Participants []entity.AuthenticationParticipant `json:"participants"`
+}
+
+func NewChallengeClosed(challenge *entity.AuthenticationChallenge,
+	participants []*entity.AuthenticationParticipant) *ChallengeClosed {
+	event := &ChallengeClosed{
+		BaseEvent:    cqrs.NewBaseEvent(challenge.ID),
+		Challenge:    *challenge,
+		Participants: make([]entity.AuthenticationParticipant, 0, len(participants)),
+	}
+	for _, participant := range participants {
+		event.Participants = append(event.Participants, *participant)
+	}
+	return event
+}
+
+func (*ChallengeClosed) EventName() string {
+	return ChallengeClosedEvent
+}
+
+// DaysLeftReminder до конца вызова осталось DaysLeft дней, участникам отправляется напоминание
+type DaysLeftReminder struct {
+	cqrs.BaseEvent
+	Challenge    entity.AuthenticationChallenge     `json:"challenge"`
+	Participants []entity.AuthenticationParticipant `json:"participants"`
+	DaysLeft     int                                `json:"days_left"`
+}
+
+func NewDaysLeftReminder(challenge *entity.AuthenticationChallenge,
+	participants []*entity.AuthenticationParticipant, daysLeft int) *DaysLeftReminder {
+	event := &DaysLeftReminder{
+		BaseEvent:    cqrs.NewBaseEvent(challenge.ID),
+		Challenge:    *challenge,
+		Participants: make([]entity.AuthenticationParticipant, 0, len(participants)),
+		DaysLeft:     daysLeft,
+	}
+	for _, participant := range participants {
+		event.Participants = append(event.Participants, *participant)
+	}
+	return event
+}
+
+func (*DaysLeftReminder) EventName() string {
+	return DaysLeftReminderEvent
+}
+
+// ErrUnknownEvent событие с таким именем не объявлено в пакете
+var ErrUnknownEvent = errors.New("unknown event")
+
+// registry конструкторы событий по имени для восстановления из outbox
+var registry = map[string]func() cqrs.Event{
+	ChallengeCreatedEvent:      func() cqrs.Event { return &ChallengeCreated{} },
+	ChallengeUpdatedEvent:      func() cqrs.Event { return &ChallengeUpdated{} },
+	ChallengeDeletedEvent:      func() cqrs.Event { return &ChallengeDeleted{} },
+	ChallengeRestoredEvent:     func() cqrs.Event { return &ChallengeRestored{} },
+	ParticipantRegisteredEvent: func() cqrs.Event { return &ParticipantRegistered{} },
+	ChallengeClosedEvent:       func() cqrs.Event { return &ChallengeClosed{} },
+	DaysLeftReminderEvent:      func() cqrs.Event { return &DaysLeftReminder{} },
+}
+
+// Decode восстанавливает событие name из JSON, сохраненного в outbox
+func Decode(name string, payload []byte) (cqrs.Event, error) {
+	newEvent, ok := registry[name]
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	event := newEvent()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
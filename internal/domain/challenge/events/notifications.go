@@ -0,0 +1,77 @@
+package events
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"time"
+)
+
+// SubscribeNotifications подписывает рассылку уведомлений на доменные события в процессе.
+// Используется, только если выключен outbox: иначе уведомления рассылает его приемник
+func SubscribeNotifications(bus *cqrs.EventBus, notifier notifier_interface.NotifierInterface) {
+	bus.SubscribeAll(func(ctx context.Context, event cqrs.Event) error {
+		for _, notification := range Notifications(event) {
+			notifier.Notify(ctx, notification)
+		}
+		return nil
+	})
+}
+
+// Notifications уведомления, которые нужно разослать по событию
+func Notifications(event cqrs.Event) []notifier_interface.Notification {
+	switch event := event.(type) {
+	case *ChallengeCreated:
+		return []notifier_interface.Notification{{
+			Event:      notifier_interface.EventChallengeCreated,
+			Recipients: []notifier_interface.Recipient{{UserID: event.Challenge.CreatorID}},
+			Data:       ChallengeNotificationData(&event.Challenge),
+		}}
+	case *ParticipantRegistered:
+		return []notifier_interface.Notification{{
+			Event:      notifier_interface.EventRegistrationConfirmed,
+			Recipients: []notifier_interface.Recipient{ParticipantRecipient(&event.Participant)},
+			Data:       ChallengeNotificationData(&event.Challenge),
+		}}
+	case *ChallengeClosed:
+		recipients := make([]notifier_interface.Recipient, 0, len(event.Participants))
+		var finished []notifier_interface.Recipient
+		for i := range event.Participants {
+			recipients = append(recipients, ParticipantRecipient(&event.Participants[i]))
+			if event.Participants[i].Status == entity.ParticipantStatusCompleted {
+				finished = append(finished, ParticipantRecipient(&event.Participants[i]))
+			}
+		}
+		data := ChallengeNotificationData(&event.Challenge)
+		return []notifier_interface.Notification{
+			{Event: notifier_interface.EventChallengeClosed, Recipients: recipients, Data: data},
+			{Event: notifier_interface.EventParticipantFinished, Recipients: finished, Data: data},
+		}
+	case *DaysLeftReminder:
+		recipients := make([]notifier_interface.Recipient, 0, len(event.Participants))
+		for i := range event.Participants {
+			recipients = append(recipients, ParticipantRecipient(&event.Participants[i]))
+		}
+		data := ChallengeNotificationData(&event.Challenge)
+		data["DaysLeft"] = event.DaysLeft
+		return []notifier_interface.Notification{
+			{Event: notifier_interface.EventDaysLeft, Recipients: recipients, Data: data},
+		}
+	}
+	return nil
+}
+
+// ChallengeNotificationData данные вызова, доступные в шаблонах уведомлений
+func ChallengeNotificationData(challenge *entity.AuthenticationChallenge) map[string]any {
+	return map[string]any{
+		"ChallengeID":   challenge.ID,
+		"ChallengeName": challenge.Name,
+		"StartDate":     challenge.StartDate.Format(time.DateOnly),
+		"EndDate":       challenge.EndDate.Format(time.DateOnly),
+	}
+}
+
+func ParticipantRecipient(participant *entity.AuthenticationParticipant) notifier_interface.Recipient {
+	return notifier_interface.Recipient{UserID: participant.UserID, TeamID: participant.TeamID}
+}
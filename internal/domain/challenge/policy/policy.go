@@ -0,0 +1,106 @@
+// Package policy проверяет права пользователя на операции с вызовами.
+// Проверки выполняются в обработчиках команд, поэтому действуют для любого транспорта.
+package policy
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/infrastructure/lib/auth"
+	"context"
+)
+
+var ErrForbidden = domainerr.Forbidden("forbidden", "forbidden")
+
+// ForbiddenError отказ в доступе с причиной, которую можно показать клиенту
+type ForbiddenError struct {
+	Reason string
+}
+
+func (e *ForbiddenError) Error() string {
+	return "forbidden: " + e.Reason
+}
+
+func (e *ForbiddenError) Unwrap() error {
+	return ErrForbidden
+}
+
+func deny(reason string) error {
+	return &ForbiddenError{Reason: reason}
+}
+
+func principal(ctx context.Context) (*auth.Principal, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, deny("authentication required")
+	}
+	return p, nil
+}
+
+func privileged(p *auth.Principal) bool {
+	return p.HasRole(auth.RoleAdmin) || p.HasRole(auth.RoleSystem)
+}
+
+// AuthorizeManage изменять вызов могут создатель, соорганизаторы и администраторы
+func AuthorizeManage(ctx context.Context, challenge *entity.AuthenticationChallenge) error {
+	p, err := principal(ctx)
+	if err != nil {
+		return err
+	}
+	if privileged(p) || challenge.IsOrganizer(p.UserID) {
+		return nil
+	}
+	return deny("only the creator, co-organizers or admins may manage this challenge")
+}
+
+// AuthorizeOwnership менять создателя и состав соорганизаторов могут только создатель и администраторы
+func AuthorizeOwnership(ctx context.Context, challenge *entity.AuthenticationChallenge) error {
+	p, err := principal(ctx)
+	if err != nil {
+		return err
+	}
+	if privileged(p) || challenge.CreatorID == p.UserID {
+		return nil
+	}
+	return deny("only the creator or admins may change challenge ownership")
+}
+
+// ResolveCreator определяет создателя нового вызова: по умолчанию это текущий пользователь,
+// создавать вызовы от имени другого пользователя могут только администраторы
+func ResolveCreator(ctx context.Context, requestedCreatorID int64) (int64, error) {
+	p, err := principal(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if requestedCreatorID == 0 || requestedCreatorID == p.UserID {
+		return p.UserID, nil
+	}
+	if privileged(p) {
+		return requestedCreatorID, nil
+	}
+	return 0, deny("only admins may create challenges on behalf of another user")
+}
+
+// AuthorizeActAs участник может регистрироваться и отмечать прогресс только от своего имени
+func AuthorizeActAs(ctx context.Context, userID int64) error {
+	p, err := principal(ctx)
+	if err != nil {
+		return err
+	}
+	if privileged(p) || p.UserID == userID {
+		return nil
+	}
+	return deny("participants may only act on their own behalf")
+}
+
+// AuthorizeTeam действовать от имени команды могут ее участники и администраторы.
+// Состав команд пользователя берется из токена
+func AuthorizeTeam(ctx context.Context, teamID int64) error {
+	p, err := principal(ctx)
+	if err != nil {
+		return err
+	}
+	if privileged(p) || p.IsTeamMember(teamID) {
+		return nil
+	}
+	return deny("only team members may act on behalf of the team")
+}
@@ -0,0 +1,132 @@
+package policy
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/infrastructure/lib/auth"
+	"context"
+	"errors"
+	"testing"
+)
+
+var (
+	creator     = &auth.Principal{UserID: 1}
+	coOrganizer = &auth.Principal{UserID: 2}
+	stranger    = &auth.Principal{UserID: 3, TeamIDs: []int64{70}}
+	admin       = &auth.Principal{UserID: 4, Roles: []string{auth.RoleAdmin}}
+	system      = auth.SystemPrincipal()
+)
+
+func asPrincipal(p *auth.Principal) context.Context {
+	if p == nil {
+		return context.Background()
+	}
+	return auth.WithPrincipal(context.Background(), p)
+}
+
+func checkDecision(t *testing.T, err error, wantAllowed bool) {
+	t.Helper()
+	if wantAllowed {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	var forbidden *ForbiddenError
+	if !errors.As(err, &forbidden) || !errors.Is(err, ErrForbidden) {
+		t.Fatalf("err = %v, want ForbiddenError wrapping ErrForbidden", err)
+	}
+}
+
+func TestAuthorizeChallenge(t *testing.T) {
+	challenge := &entity.AuthenticationChallenge{CreatorID: 1, CoOrganizerIDs: []int64{2}}
+	tests := []struct {
+		name          string
+		principal     *auth.Principal
+		wantManage    bool
+		wantOwnership bool
+	}{
+		{name: "creator", principal: creator, wantManage: true, wantOwnership: true},
+		{name: "co-organizer", principal: coOrganizer, wantManage: true},
+		{name: "stranger", principal: stranger},
+		{name: "admin", principal: admin, wantManage: true, wantOwnership: true},
+		{name: "system", principal: system, wantManage: true, wantOwnership: true},
+		{name: "anonymous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := asPrincipal(tt.principal)
+			t.Run("manage", func(t *testing.T) {
+				checkDecision(t, AuthorizeManage(ctx, challenge), tt.wantManage)
+			})
+			t.Run("ownership", func(t *testing.T) {
+				checkDecision(t, AuthorizeOwnership(ctx, challenge), tt.wantOwnership)
+			})
+		})
+	}
+}
+
+func TestResolveCreator(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   *auth.Principal
+		requested   int64
+		wantCreator int64
+		wantAllowed bool
+	}{
+		{name: "defaults to the current user", principal: stranger, wantCreator: 3, wantAllowed: true},
+		{name: "explicit self", principal: stranger, requested: 3, wantCreator: 3, wantAllowed: true},
+		{name: "on behalf of another user", principal: stranger, requested: 9},
+		{name: "admin on behalf of another user", principal: admin, requested: 9, wantCreator: 9, wantAllowed: true},
+		{name: "anonymous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creatorID, err := ResolveCreator(asPrincipal(tt.principal), tt.requested)
+			checkDecision(t, err, tt.wantAllowed)
+			if creatorID != tt.wantCreator {
+				t.Errorf("creator = %d, want %d", creatorID, tt.wantCreator)
+			}
+		})
+	}
+}
+
+func TestAuthorizeActAs(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   *auth.Principal
+		userID      int64
+		wantAllowed bool
+	}{
+		{name: "self", principal: stranger, userID: 3, wantAllowed: true},
+		{name: "another user", principal: stranger, userID: 1},
+		{name: "admin", principal: admin, userID: 1, wantAllowed: true},
+		{name: "system", principal: system, userID: 1, wantAllowed: true},
+		{name: "anonymous", userID: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDecision(t, AuthorizeActAs(asPrincipal(tt.principal), tt.userID), tt.wantAllowed)
+		})
+	}
+}
+
+func TestAuthorizeTeam(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   *auth.Principal
+		teamID      int64
+		wantAllowed bool
+	}{
+		{name: "member", principal: stranger, teamID: 70, wantAllowed: true},
+		{name: "not a member", principal: stranger, teamID: 71},
+		{name: "no teams in token", principal: creator, teamID: 70},
+		{name: "admin", principal: admin, teamID: 71, wantAllowed: true},
+		{name: "system", principal: system, teamID: 71, wantAllowed: true},
+		{name: "anonymous", teamID: 70},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDecision(t, AuthorizeTeam(asPrincipal(tt.principal), tt.teamID), tt.wantAllowed)
+		})
+	}
+}
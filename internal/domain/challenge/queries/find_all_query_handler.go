@@ -2,6 +2,7 @@ package queries
 
 import (
 	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
 	"context"
@@ -9,7 +10,7 @@ import (
 )
 
 type FindAllQueryHandler struct {
-	cqrs.QueryHandler[FindAllQuery]
+	cqrs.QueryHandler[*FindAllQuery, []*entity.AuthenticationChallenge]
 	log  *slog.Logger
 	cfg  *config.Config
 	repo repository_interface.ChallengeRepositoryInterface
@@ -24,10 +25,8 @@ func NewFindAllQueryHandler(log *slog.Logger,
 		repo: repo,
 	}
 }
-func (handler *FindAllQueryHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
-	handler.log.Info("FindAllQueryHandler")
-	_ = query.(*FindAllQuery)
-	result, err := handler.repo.FindAll()
+func (handler *FindAllQueryHandler) Handle(ctx context.Context, query *FindAllQuery) ([]*entity.AuthenticationChallenge, error) {
+	result, err := handler.repo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -2,15 +2,15 @@ package queries
 
 import (
 	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/domainerr"
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
 	"context"
-	"errors"
 	"log/slog"
 )
 
 type FindByParamsQueryHandler struct {
-	cqrs.QueryHandler[FindByParamsQuery]
+	cqrs.QueryHandler[*FindByParamsQuery, *repository_interface.ChallengePage]
 	log  *slog.Logger
 	cfg  *config.Config
 	repo repository_interface.ChallengeRepositoryInterface
@@ -25,18 +25,12 @@ func NewFindByParamsQueryHandler(log *slog.Logger, cfg *config.Config,
 	}
 }
 
-func (handler *FindByParamsQueryHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
-	handler.log.Info("FindByParamsQueryHandler")
-	findByParamsQuery, ok := query.(*FindByParamsQuery)
-	if !ok {
-		return nil, errors.New("invalid query type")
+func (handler *FindByParamsQueryHandler) Handle(ctx context.Context, query *FindByParamsQuery) (*repository_interface.ChallengePage, error) {
+	if query.Params == nil {
+		return nil, domainerr.Validation("missing_parameters", "missing parameters")
 	}
 
-	if findByParamsQuery.Params == nil {
-		return nil, errors.New("missing parameters")
-	}
-
-	result, err := handler.repo.FindByParams(findByParamsQuery.Params)
+	result, err := handler.repo.FindByParams(ctx, query.Params)
 	if err != nil {
 		return nil, err
 	}
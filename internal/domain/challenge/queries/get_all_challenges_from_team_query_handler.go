@@ -5,12 +5,11 @@ import (
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
 	"context"
-	"errors"
 	"log/slog"
 )
 
 type GetAllChallengesFromTeamQueryHandler struct {
-	cqrs.QueryHandler[GetAllChallengesFromTeamQuery]
+	cqrs.QueryHandler[*GetAllChallengesFromTeamQuery, *repository_interface.ChallengePage]
 	log  *slog.Logger
 	cfg  *config.Config
 	repo repository_interface.ChallengeRepositoryInterface
@@ -25,14 +24,14 @@ func NewGetAllChallengesFromTeamQueryHandler(log *slog.Logger, cfg *config.Confi
 	}
 }
 
-func (handler *GetAllChallengesFromTeamQueryHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
-	handler.log.Info("GetAllChallengesFromTeamQueryHandler")
-	getAllChallengesFromTeamQuery, ok := query.(*GetAllChallengesFromTeamQuery)
-	if !ok {
-		return nil, errors.New("invalid query type")
+func (handler *GetAllChallengesFromTeamQueryHandler) Handle(ctx context.Context, query *GetAllChallengesFromTeamQuery) (*repository_interface.ChallengePage, error) {
+	params := repository_interface.AuthenticationChallengeParams{}
+	if query.Params != nil {
+		params = *query.Params
 	}
+	params.ParticipantTeamID = &query.TeamID
 
-	result, err := handler.repo.GetAllChallengesFromTeam(getAllChallengesFromTeamQuery.TeamID)
+	result, err := handler.repo.FindByParams(ctx, &params)
 	if err != nil {
 		return nil, err
 	}
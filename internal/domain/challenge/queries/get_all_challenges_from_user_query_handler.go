@@ -5,12 +5,11 @@ import (
 	"challenge-service/internal/domain/challenge/usecases/repository_interface"
 	"challenge-service/internal/infrastructure/cqrs"
 	"context"
-	"errors"
 	"log/slog"
 )
 
 type GetAllChallengesFromUserQueryHandler struct {
-	cqrs.QueryHandler[GetAllChallengesFromUserQuery]
+	cqrs.QueryHandler[*GetAllChallengesFromUserQuery, *repository_interface.ChallengePage]
 	log  *slog.Logger
 	cfg  *config.Config
 	repo repository_interface.ChallengeRepositoryInterface
@@ -25,14 +24,14 @@ func NewGetAllChallengesFromUserQueryHandler(log *slog.Logger, cfg *config.Confi
 	}
 }
 
-func (handler *GetAllChallengesFromUserQueryHandler) Handle(ctx context.Context, query cqrs.Query) (interface{}, error) {
-	handler.log.Info("GetAllChallengesFromUserQueryHandler")
-	getAllChallengesFromUserQuery, ok := query.(*GetAllChallengesFromUserQuery)
-	if !ok {
-		return nil, errors.New("invalid query type")
+func (handler *GetAllChallengesFromUserQueryHandler) Handle(ctx context.Context, query *GetAllChallengesFromUserQuery) (*repository_interface.ChallengePage, error) {
+	params := repository_interface.AuthenticationChallengeParams{}
+	if query.Params != nil {
+		params = *query.Params
 	}
+	params.ParticipantUserID = &query.UserID
 
-	result, err := handler.repo.GetAllChallengesFromUser(getAllChallengesFromUserQuery.UserID)
+	result, err := handler.repo.FindByParams(ctx, &params)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type GetChallengeQueryHandler struct {
+	cqrs.QueryHandler[*GetChallengeQuery, *entity.AuthenticationChallenge]
+	log  *slog.Logger
+	cfg  *config.Config
+	repo repository_interface.ChallengeRepositoryInterface
+}
+
+func NewGetChallengeQueryHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface) *GetChallengeQueryHandler {
+	return &GetChallengeQueryHandler{
+		log:  log,
+		cfg:  cfg,
+		repo: repo,
+	}
+}
+
+func (handler *GetChallengeQueryHandler) Handle(ctx context.Context, query *GetChallengeQuery) (*entity.AuthenticationChallenge, error) {
+	return handler.repo.FindByID(ctx, query.ChallengeID)
+}
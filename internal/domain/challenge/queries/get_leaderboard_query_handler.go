@@ -0,0 +1,49 @@
+package queries
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/policy"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type GetLeaderboardQueryHandler struct {
+	cqrs.QueryHandler[*GetLeaderboardQuery, *entity.Leaderboard]
+	log  *slog.Logger
+	cfg  *config.Config
+	repo repository_interface.ChallengeRepositoryInterface
+}
+
+func NewGetLeaderboardQueryHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface) *GetLeaderboardQueryHandler {
+	return &GetLeaderboardQueryHandler{
+		log:  log,
+		cfg:  cfg,
+		repo: repo,
+	}
+}
+
+func (handler *GetLeaderboardQueryHandler) Handle(ctx context.Context, query *GetLeaderboardQuery) (*entity.Leaderboard, error) {
+	challenge, err := handler.repo.FindByID(ctx, query.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	// в командном вызове "я" - команда из запроса, место показывается только ее участникам
+	me := query.UserID
+	if challenge.IsTeam {
+		me = query.TeamID
+		if me != 0 {
+			if err := policy.AuthorizeTeam(ctx, me); err != nil {
+				return nil, err
+			}
+		}
+	}
+	entries, participants, err := handler.repo.RankParticipants(ctx, *challenge, query.Offset, query.Limit, me)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewLeaderboard(*challenge, entries, participants, query.Offset, query.Limit, me), nil
+}
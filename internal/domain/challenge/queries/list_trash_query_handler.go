@@ -0,0 +1,30 @@
+package queries
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+)
+
+type ListTrashQueryHandler struct {
+	cqrs.QueryHandler[*ListTrashQuery, []*entity.AuthenticationChallenge]
+	log  *slog.Logger
+	cfg  *config.Config
+	repo repository_interface.ChallengeRepositoryInterface
+}
+
+func NewListTrashQueryHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface) *ListTrashQueryHandler {
+	return &ListTrashQueryHandler{
+		log:  log,
+		cfg:  cfg,
+		repo: repo,
+	}
+}
+
+func (handler *ListTrashQueryHandler) Handle(ctx context.Context, query *ListTrashQuery) ([]*entity.AuthenticationChallenge, error) {
+	return handler.repo.FindDeleted(ctx, query.CreatorID)
+}
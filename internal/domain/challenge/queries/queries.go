@@ -5,6 +5,20 @@ import (
 	"challenge-service/internal/infrastructure/cqrs"
 )
 
+// All запросы сервиса, для каждого при старте должен быть зарегистрирован ровно один обработчик
+func All() []cqrs.Query {
+	return []cqrs.Query{
+		NewFindAllQuery(),
+		NewEmptyFindByParamsQuery(),
+		NewEmptyGetChallengeQuery(),
+		NewEmptyListTrashQuery(),
+		NewEmptyGetAllChallengesFromUserQuery(),
+		NewEmptyGetAllChallengesFromTeamQuery(),
+		NewEmptyGetLeaderboardQuery(),
+		NewEmptySearchChallengesQuery(),
+	}
+}
+
 type FindAllQuery struct {
 	cqrs.BaseQuery
 }
@@ -15,7 +29,7 @@ func NewFindAllQuery() *FindAllQuery {
 
 type FindByParamsQuery struct {
 	cqrs.BaseQuery
-	Params *repository_interface.AuthenticationChallengeParams `json:"params"`
+	Params *repository_interface.AuthenticationChallengeParams `json:"params" validate:"required"`
 }
 
 func NewFindByParamsQuery(id int64, params *repository_interface.AuthenticationChallengeParams) *FindByParamsQuery {
@@ -29,15 +43,51 @@ func NewEmptyFindByParamsQuery() *FindByParamsQuery {
 	return &FindByParamsQuery{}
 }
 
+type GetChallengeQuery struct {
+	cqrs.BaseQuery
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+}
+
+func NewGetChallengeQuery(id int64, challengeID int64) *GetChallengeQuery {
+	return &GetChallengeQuery{
+		BaseQuery:   cqrs.NewBaseQuery(id),
+		ChallengeID: challengeID,
+	}
+}
+
+func NewEmptyGetChallengeQuery() *GetChallengeQuery {
+	return &GetChallengeQuery{}
+}
+
+// ListTrashQuery вызовы создателя, перемещенные в корзину
+type ListTrashQuery struct {
+	cqrs.BaseQuery
+	CreatorID int64 `json:"creator_id" validate:"required"`
+}
+
+func NewListTrashQuery(id int64, creatorID int64) *ListTrashQuery {
+	return &ListTrashQuery{
+		BaseQuery: cqrs.NewBaseQuery(id),
+		CreatorID: creatorID,
+	}
+}
+
+func NewEmptyListTrashQuery() *ListTrashQuery {
+	return &ListTrashQuery{}
+}
+
 type GetAllChallengesFromUserQuery struct {
 	cqrs.BaseQuery
-	UserID string `json:"user_id"`
+	UserID int64                                               `json:"user_id" validate:"required"`
+	Params *repository_interface.AuthenticationChallengeParams `json:"params" validate:"required"`
 }
 
-func NewGetAllChallengesFromUserQuery(id int64, userID string) *GetAllChallengesFromUserQuery {
+func NewGetAllChallengesFromUserQuery(id int64, userID int64,
+	params *repository_interface.AuthenticationChallengeParams) *GetAllChallengesFromUserQuery {
 	return &GetAllChallengesFromUserQuery{
 		BaseQuery: cqrs.NewBaseQuery(id),
 		UserID:    userID,
+		Params:    params,
 	}
 }
 
@@ -47,16 +97,60 @@ func NewEmptyGetAllChallengesFromUserQuery() *GetAllChallengesFromUserQuery {
 
 type GetAllChallengesFromTeamQuery struct {
 	cqrs.BaseQuery
-	TeamID string `json:"team_id"`
+	TeamID int64                                               `json:"team_id" validate:"required"`
+	Params *repository_interface.AuthenticationChallengeParams `json:"params" validate:"required"`
 }
 
-func NewGetAllChallengesFromTeamQuery(id int64, teamID string) *GetAllChallengesFromTeamQuery {
+func NewGetAllChallengesFromTeamQuery(id int64, teamID int64,
+	params *repository_interface.AuthenticationChallengeParams) *GetAllChallengesFromTeamQuery {
 	return &GetAllChallengesFromTeamQuery{
 		BaseQuery: cqrs.NewBaseQuery(id),
 		TeamID:    teamID,
+		Params:    params,
 	}
 }
 
 func NewEmptyGetAllChallengesFromTeamQuery() *GetAllChallengesFromTeamQuery {
 	return &GetAllChallengesFromTeamQuery{}
 }
+
+type GetLeaderboardQuery struct {
+	cqrs.BaseQuery
+	ChallengeID int64 `json:"challenge_id" validate:"required"`
+	Limit       int   `json:"limit" validate:"gt=0,lte=100"`
+	Offset      int   `json:"offset" validate:"gte=0"`
+	UserID      int64 `json:"user_id" validate:"gte=0"`
+	TeamID      int64 `json:"team_id" validate:"gte=0"`
+}
+
+func NewGetLeaderboardQuery(id int64, challengeID int64, limit int, offset int, userID int64,
+	teamID int64) *GetLeaderboardQuery {
+	return &GetLeaderboardQuery{
+		BaseQuery:   cqrs.NewBaseQuery(id),
+		ChallengeID: challengeID,
+		Limit:       limit,
+		Offset:      offset,
+		UserID:      userID,
+		TeamID:      teamID,
+	}
+}
+
+func NewEmptyGetLeaderboardQuery() *GetLeaderboardQuery {
+	return &GetLeaderboardQuery{}
+}
+
+type SearchChallengesQuery struct {
+	cqrs.BaseQuery
+	Params *repository_interface.ChallengeSearchParams `json:"params" validate:"required"`
+}
+
+func NewSearchChallengesQuery(id int64, params *repository_interface.ChallengeSearchParams) *SearchChallengesQuery {
+	return &SearchChallengesQuery{
+		BaseQuery: cqrs.NewBaseQuery(id),
+		Params:    params,
+	}
+}
+
+func NewEmptySearchChallengesQuery() *SearchChallengesQuery {
+	return &SearchChallengesQuery{}
+}
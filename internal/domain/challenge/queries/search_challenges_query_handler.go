@@ -0,0 +1,39 @@
+package queries
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/domainerr"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"log/slog"
+	"strings"
+)
+
+type SearchChallengesQueryHandler struct {
+	cqrs.QueryHandler[*SearchChallengesQuery, *repository_interface.ChallengeSearchPage]
+	log  *slog.Logger
+	cfg  *config.Config
+	repo repository_interface.ChallengeRepositoryInterface
+}
+
+func NewSearchChallengesQueryHandler(log *slog.Logger, cfg *config.Config,
+	repo repository_interface.ChallengeRepositoryInterface) *SearchChallengesQueryHandler {
+	return &SearchChallengesQueryHandler{
+		log:  log,
+		cfg:  cfg,
+		repo: repo,
+	}
+}
+
+func (handler *SearchChallengesQueryHandler) Handle(ctx context.Context, query *SearchChallengesQuery) (*repository_interface.ChallengeSearchPage, error) {
+	if query.Params == nil || strings.TrimSpace(query.Params.Query) == "" {
+		return nil, domainerr.Validation("missing_search_query", "missing search query")
+	}
+
+	result, err := handler.repo.Search(ctx, query.Params)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package notifier_interface
+
+import (
+	"context"
+)
+
+type Event string
+
+const (
+	EventChallengeCreated      Event = "challenge_created"
+	EventRegistrationConfirmed Event = "registration_confirmed"
+	EventDaysLeft              Event = "days_left"
+	EventChallengeClosed       Event = "challenge_closed"
+	EventParticipantFinished   Event = "participant_finished"
+)
+
+// Recipient получатель уведомления: пользователь или команда
+type Recipient struct {
+	UserID int64 `json:"user_id,omitempty"`
+	TeamID int64 `json:"team_id,omitempty"`
+}
+
+type Notification struct {
+	Event      Event
+	Recipients []Recipient
+	Data       map[string]any
+}
+
+type NotifierInterface interface {
+	// Notify ставит уведомление в очередь, не дожидаясь отправки
+	Notify(ctx context.Context, notification Notification)
+}
@@ -2,24 +2,77 @@ package repository_interface
 
 import (
 	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"time"
 )
 
+// AuthenticationChallengeParams фильтры, сортировка и пагинация выдачи вызовов.
+// Пустые (nil) фильтры не применяются. Имена в json совпадают с query-параметрами, под ними возвращаются ошибки валидации
 type AuthenticationChallengeParams struct {
-	Name   *string
-	Type   *string // семейный, личный, общий(групповой)
-	IsTeam *bool
+	Name       *string `json:"name,omitempty" validate:"omitnil,max=255"`
+	Type       *string `json:"type,omitempty" validate:"omitnil,oneof=family personal group"`
+	IsTeam     *bool   `json:"is_team,omitempty"`
+	IsFinished *bool   `json:"is_finished,omitempty"`
+	CreatorID  *int64  `json:"creator_id,omitempty" validate:"omitnil,gt=0"`
+
+	// участник вызова: пользователь или команда
+	ParticipantUserID *int64 `json:"participant_user_id,omitempty" validate:"omitnil,gt=0"`
+	ParticipantTeamID *int64 `json:"participant_team_id,omitempty" validate:"omitnil,gt=0"`
+
+	StartFrom *time.Time `json:"start_from,omitempty"`
+	StartTo   *time.Time `json:"start_to,omitempty"`
+	EndFrom   *time.Time `json:"end_from,omitempty"`
+	EndTo     *time.Time `json:"end_to,omitempty"`
+
+	SortBy   string           `json:"sort" validate:"omitempty,oneof=start_date end_date name participants"` // start_date (по умолчанию), end_date, name, participants
+	SortDesc bool             `json:"desc"`
+	Limit    int              `json:"limit" validate:"gte=0,lte=100"` // не больше MaxPageSize
+	Cursor   *ChallengeCursor `json:"cursor,omitempty"`
 }
 
 type ChallengeRepositoryInterface interface {
-	Create(challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error)
-	Delete(challengeID int64) error
-	Update(challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error)
-	FindAll() ([]*entity.AuthenticationChallenge, error)
-	FindByParams(params *AuthenticationChallengeParams) ([]*entity.AuthenticationChallenge, error)
-	GetAllChallengesFromUser(userID string) ([]*entity.AuthenticationChallenge, error)
-	GetAllChallengesFromTeam(teamID string) ([]*entity.AuthenticationChallenge, error)
-
-	RegisterUserOnChallenge(userID int64, challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error)
-	RegisterTeamOnChallenge(teamID int64, challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error)
-	CloseChallenge(challengeID int64) (*entity.AuthenticationChallenge, error)
+	Create(ctx context.Context, challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error)
+	Delete(ctx context.Context, challengeID int64) error
+	// Update записывает только поля fields (имена полей структуры), если версия вызова не изменилась
+	// с момента чтения, иначе возвращает entity.ErrStaleVersion
+	Update(ctx context.Context, challenge entity.AuthenticationChallenge, fields ...string) (*entity.AuthenticationChallenge, error)
+	FindByID(ctx context.Context, challengeID int64) (*entity.AuthenticationChallenge, error)
+	FindAll(ctx context.Context) ([]*entity.AuthenticationChallenge, error)
+	FindByParams(ctx context.Context, params *AuthenticationChallengeParams) (*ChallengePage, error)
+	Search(ctx context.Context, params *ChallengeSearchParams) (*ChallengeSearchPage, error)
+
+	// Методы корзины. Delete перемещает вызов в корзину, остальные методы его больше не видят
+	FindDeleted(ctx context.Context, creatorID int64) ([]*entity.AuthenticationChallenge, error)
+	FindDeletedByID(ctx context.Context, challengeID int64) (*entity.AuthenticationChallenge, error)
+	Restore(ctx context.Context, challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error)
+	// PurgeDeleted окончательно удаляет до limit вызовов, перемещенных в корзину раньше before, вместе с участниками.
+	// Возвращает удаленные вызовы, чтобы вызывающий удалил их изображения из хранилища
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) ([]*entity.AuthenticationChallenge, error)
+
+	RegisterUserOnChallenge(ctx context.Context, userID int64, challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error)
+	RegisterTeamOnChallenge(ctx context.Context, teamID int64, challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error)
+	UpdateStatus(ctx context.Context, challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error)
+	FindByStatus(ctx context.Context, status entity.ChallengeStatus) ([]*entity.AuthenticationChallenge, error)
+
+	GetParticipants(ctx context.Context, challengeID int64) ([]*entity.AuthenticationParticipant, error)
+	// RankParticipants ранжирует участников вызова по правилам entity.Leaderboard и возвращает места
+	// с offset+1 по offset+limit, место субъекта me, если он участвует, и число ранжированных субъектов
+	RankParticipants(ctx context.Context, challenge entity.AuthenticationChallenge, offset int, limit int,
+		me int64) ([]entity.LeaderboardEntry, int, error)
+	FindParticipant(ctx context.Context, challengeID int64, userID int64, teamID int64) (*entity.AuthenticationParticipant, error)
+	// LockParticipant как FindParticipant, но блокирует строку участника до конца транзакции,
+	// чтобы одновременные изменения прогресса не затирали друг друга
+	LockParticipant(ctx context.Context, challengeID int64, userID int64, teamID int64) (*entity.AuthenticationParticipant, error)
+	UpdateParticipantStatus(ctx context.Context, participantID int64, status string) error
+	UpdateParticipantProgress(ctx context.Context, participantID int64, progress entity.ParticipantProgress) (*entity.AuthenticationParticipant, error)
+
+	// ClaimReminder отмечает напоминание "осталось daysLeft дней" отправленным. false - его уже
+	// отправил этот или другой экземпляр сервиса
+	ClaimReminder(ctx context.Context, challengeID int64, daysLeft int) (bool, error)
+
+	// Transaction выполняет fn в транзакции, repo внутри fn работает в ее рамках
+	Transaction(ctx context.Context, fn func(repo ChallengeRepositoryInterface) error) error
+	// AppendEvents сохраняет события в outbox для последующей доставки
+	AppendEvents(ctx context.Context, events ...cqrs.Event) error
 }
@@ -0,0 +1,66 @@
+package repository_interface
+
+import (
+	"challenge-service/internal/domain/challenge/domainerr"
+	"challenge-service/internal/domain/challenge/entity"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	SortByStartDate    = "start_date"
+	SortByEndDate      = "end_date"
+	SortByName         = "name"
+	SortByParticipants = "participants"
+
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor = domainerr.Validation("invalid_cursor", "invalid cursor")
+	ErrInvalidSort   = domainerr.Validation("invalid_sort", "invalid sort key")
+)
+
+// ChallengeCursor позиция в выдаче: значение ключа сортировки и ID последнего вызова на странице.
+// Курсор привязан к сортировке, с которой он был выдан
+type ChallengeCursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v"`
+	ID     int64  `json:"id"`
+}
+
+// Encode непрозрачное представление курсора для передачи клиенту
+func (c ChallengeCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeChallengeCursor(value string) (*ChallengeCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor ChallengeCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// ValidSortKey проверяет, что по ключу можно сортировать выдачу
+func ValidSortKey(sortBy string) error {
+	switch sortBy {
+	case SortByStartDate, SortByEndDate, SortByName, SortByParticipants:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidSort, sortBy)
+}
+
+// ChallengePage страница выдачи. NextCursor пуст на последней странице
+type ChallengePage struct {
+	Items      []*entity.AuthenticationChallenge `json:"items"`
+	NextCursor string                            `json:"next_cursor,omitempty"`
+}
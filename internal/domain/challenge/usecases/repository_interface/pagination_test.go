@@ -0,0 +1,72 @@
+package repository_interface
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestChallengeCursorRoundTrip(t *testing.T) {
+	tests := []ChallengeCursor{
+		{SortBy: SortByStartDate, Value: "2026-03-01T00:00:00Z", ID: 1},
+		{SortBy: SortByName, Desc: true, Value: "Зарядка, \"утро\"", ID: 42},
+		{SortBy: SortByParticipants, Value: "0", ID: 1 << 40},
+	}
+	for _, cursor := range tests {
+		t.Run(cursor.SortBy, func(t *testing.T) {
+			decoded, err := DecodeChallengeCursor(cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeChallengeCursor: %v", err)
+			}
+			if *decoded != cursor {
+				t.Fatalf("decoded = %+v, want %+v", *decoded, cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeChallengeCursorInvalid(t *testing.T) {
+	encode := func(value string) string { return base64.RawURLEncoding.EncodeToString([]byte(value)) }
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "empty", value: ""},
+		{name: "not base64", value: "%%%"},
+		{name: "not json", value: encode("cursor")},
+		{name: "without id", value: encode(`{"s":"name","v":"a"}`)},
+		{name: "wrong id type", value: encode(`{"s":"name","v":"a","id":"1"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeChallengeCursor(tt.value); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestValidSortKey(t *testing.T) {
+	tests := []struct {
+		sortBy  string
+		wantErr bool
+	}{
+		{sortBy: SortByStartDate},
+		{sortBy: SortByEndDate},
+		{sortBy: SortByName},
+		{sortBy: SortByParticipants},
+		{sortBy: "", wantErr: true},
+		{sortBy: "id; drop table", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			err := ValidSortKey(tt.sortBy)
+			if tt.wantErr != errors.Is(err, ErrInvalidSort) {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
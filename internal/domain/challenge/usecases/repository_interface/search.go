@@ -0,0 +1,27 @@
+package repository_interface
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+)
+
+// ChallengeSearchParams полнотекстовый поиск по названию и описанию вызова
+type ChallengeSearchParams struct {
+	Query  string  `json:"q" validate:"required,max=200"`
+	Type   *string `json:"type,omitempty" validate:"omitnil,oneof=family personal group"`
+	IsTeam *bool   `json:"is_team,omitempty"`
+	Limit  int     `json:"limit" validate:"gte=0,lte=100"` // не больше MaxPageSize
+	Offset int     `json:"offset" validate:"gte=0"`
+}
+
+// ChallengeSearchResult найденный вызов с рангом и фрагментами текста, в которых
+// совпадения выделены тегом <mark>. Остальной текст фрагментов экранирован как HTML
+type ChallengeSearchResult struct {
+	Challenge            *entity.AuthenticationChallenge `json:"challenge"`
+	Rank                 float64                         `json:"rank"`
+	NameHighlight        string                          `json:"name_highlight"`
+	DescriptionHighlight string                          `json:"description_highlight"`
+}
+
+type ChallengeSearchPage struct {
+	Items []*ChallengeSearchResult `json:"items"`
+}
@@ -0,0 +1,161 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrHandlerNotFound = errors.New("handler not registered")
+	ErrResultType      = errors.New("handler result type mismatch")
+)
+
+type Kind string
+
+const (
+	KindCommand Kind = "command"
+	KindQuery   Kind = "query"
+)
+
+// MessageInfo описание команды или запроса, которое получают middleware
+type MessageInfo struct {
+	Kind Kind
+	// Name имя типа без указателя, например CreateChallengeCommand
+	Name string
+}
+
+// HandlerFunc нетипизированное звено конвейера, обработчики приводятся к нему при регистрации
+type HandlerFunc func(ctx context.Context, message any) (any, error)
+
+// Middleware оборачивает обработчик: логирование, метрики, валидация, транзакции
+type Middleware func(info MessageInfo, next HandlerFunc) HandlerFunc
+
+type registration struct {
+	handler HandlerFunc
+	result  reflect.Type
+}
+
+// Bus шина команд и запросов. Обработчик выбирается по типу сообщения из параметра Dispatch/Ask,
+// результат возвращается типизированным. Middleware подключаются через Use до регистрации обработчиков,
+// Validate при старте проверяет, что у каждой объявленной команды и запроса ровно один обработчик
+type Bus struct {
+	middlewares []Middleware
+	handlers    map[reflect.Type]registration
+	declared    map[reflect.Type]Kind
+	errs        []error
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[reflect.Type]registration),
+		declared: make(map[reflect.Type]Kind),
+	}
+}
+
+// Use добавляет middleware, первый добавленный выполняется первым
+func (b *Bus) Use(middlewares ...Middleware) {
+	if len(b.handlers) > 0 {
+		b.errs = append(b.errs, errors.New("middleware must be added before handlers are registered"))
+		return
+	}
+	b.middlewares = append(b.middlewares, middlewares...)
+}
+
+// DeclareCommands объявляет команды, обработчики которых обязательны
+func (b *Bus) DeclareCommands(commands ...Command) {
+	for _, command := range commands {
+		b.declared[reflect.TypeOf(command)] = KindCommand
+	}
+}
+
+// DeclareQueries объявляет запросы, обработчики которых обязательны
+func (b *Bus) DeclareQueries(queries ...Query) {
+	for _, query := range queries {
+		b.declared[reflect.TypeOf(query)] = KindQuery
+	}
+}
+
+// Validate возвращает все ошибки конфигурации шины: повторные регистрации, объявленные сообщения
+// без обработчика и обработчики необъявленных сообщений
+func (b *Bus) Validate() error {
+	errs := append([]error(nil), b.errs...)
+	for messageType, kind := range b.declared {
+		if _, ok := b.handlers[messageType]; !ok {
+			errs = append(errs, fmt.Errorf("%s %s: %w", kind, typeName(messageType), ErrHandlerNotFound))
+		}
+	}
+	for messageType := range b.handlers {
+		if _, ok := b.declared[messageType]; !ok {
+			errs = append(errs, fmt.Errorf("handler registered for undeclared message %s", typeName(messageType)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterCommand регистрирует обработчик команды C с результатом R. Передается метод Handle
+// обработчика, чтобы типы команды и результата выводились из его сигнатуры
+func RegisterCommand[C Command, R any](bus *Bus, handle func(ctx context.Context, command C) (R, error)) {
+	register(bus, KindCommand, handle)
+}
+
+// RegisterQuery регистрирует обработчик запроса Q с результатом R
+func RegisterQuery[Q Query, R any](bus *Bus, handle func(ctx context.Context, query Q) (R, error)) {
+	register(bus, KindQuery, handle)
+}
+
+// Dispatch выполняет команду через конвейер middleware ее обработчика
+func Dispatch[C Command, R any](ctx context.Context, bus *Bus, command C) (R, error) {
+	return dispatch[C, R](ctx, bus, command)
+}
+
+// Ask выполняет запрос через конвейер middleware его обработчика
+func Ask[Q Query, R any](ctx context.Context, bus *Bus, query Q) (R, error) {
+	return dispatch[Q, R](ctx, bus, query)
+}
+
+func register[M any, R any](bus *Bus, kind Kind, handle func(ctx context.Context, message M) (R, error)) {
+	messageType := reflect.TypeFor[M]()
+	if _, ok := bus.handlers[messageType]; ok {
+		bus.errs = append(bus.errs, fmt.Errorf("%s %s: handler registered more than once", kind, typeName(messageType)))
+		return
+	}
+	var handler HandlerFunc = func(ctx context.Context, message any) (any, error) {
+		return handle(ctx, message.(M))
+	}
+	info := MessageInfo{Kind: kind, Name: typeName(messageType)}
+	for i := len(bus.middlewares) - 1; i >= 0; i-- {
+		handler = bus.middlewares[i](info, handler)
+	}
+	bus.handlers[messageType] = registration{handler: handler, result: reflect.TypeFor[R]()}
+}
+
+func dispatch[M any, R any](ctx context.Context, bus *Bus, message M) (R, error) {
+	var zero R
+	messageType := reflect.TypeFor[M]()
+	registered, ok := bus.handlers[messageType]
+	if !ok {
+		return zero, fmt.Errorf("%s: %w", typeName(messageType), ErrHandlerNotFound)
+	}
+	if resultType := reflect.TypeFor[R](); registered.result != resultType {
+		return zero, fmt.Errorf("%s returns %s, not %s: %w", typeName(messageType), registered.result, resultType, ErrResultType)
+	}
+	result, err := registered.handler(ctx, message)
+	if err != nil {
+		return zero, err
+	}
+	// nil без типа, например от middleware, приводится к нулевому значению R
+	typed, _ := result.(R)
+	return typed, nil
+}
+
+// typeName имя типа команды или запроса без указателя и пакета
+func typeName(t reflect.Type) string {
+	name := strings.TrimLeft(t.String(), "*")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
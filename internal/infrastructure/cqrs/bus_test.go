@@ -0,0 +1,231 @@
+package cqrs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type pingCommand struct {
+	BaseCommand
+	Value string
+}
+
+type pingQuery struct {
+	BaseQuery
+}
+
+type unusedCommand struct {
+	BaseCommand
+}
+
+func handlePing(_ context.Context, command *pingCommand) (string, error) {
+	if command.Value == "" {
+		return "", errors.New("empty ping")
+	}
+	return "pong " + command.Value, nil
+}
+
+func handlePingQuery(context.Context, *pingQuery) (int, error) {
+	return 42, nil
+}
+
+func TestBusDispatch(t *testing.T) {
+	bus := NewBus()
+	bus.DeclareCommands(&pingCommand{})
+	bus.DeclareQueries(&pingQuery{})
+	RegisterCommand(bus, handlePing)
+	RegisterQuery(bus, handlePingQuery)
+	if err := bus.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("command", func(t *testing.T) {
+		result, err := Dispatch[*pingCommand, string](ctx, bus, &pingCommand{Value: "a"})
+		if err != nil || result != "pong a" {
+			t.Fatalf("Dispatch = %q, %v", result, err)
+		}
+	})
+	t.Run("handler error", func(t *testing.T) {
+		if _, err := Dispatch[*pingCommand, string](ctx, bus, &pingCommand{}); err == nil || err.Error() != "empty ping" {
+			t.Fatalf("err = %v, want handler error", err)
+		}
+	})
+	t.Run("query", func(t *testing.T) {
+		result, err := Ask[*pingQuery, int](ctx, bus, &pingQuery{})
+		if err != nil || result != 42 {
+			t.Fatalf("Ask = %d, %v", result, err)
+		}
+	})
+	t.Run("result type mismatch", func(t *testing.T) {
+		if _, err := Dispatch[*pingCommand, int](ctx, bus, &pingCommand{Value: "a"}); !errors.Is(err, ErrResultType) {
+			t.Fatalf("err = %v, want ErrResultType", err)
+		}
+	})
+	t.Run("handler not registered", func(t *testing.T) {
+		if _, err := Dispatch[*unusedCommand, string](ctx, bus, &unusedCommand{}); !errors.Is(err, ErrHandlerNotFound) {
+			t.Fatalf("err = %v, want ErrHandlerNotFound", err)
+		}
+	})
+}
+
+func TestBusValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(bus *Bus)
+		wantErrs  []string
+	}{
+		{
+			name: "declared message without handler",
+			configure: func(bus *Bus) {
+				bus.DeclareCommands(&pingCommand{}, &unusedCommand{})
+				RegisterCommand(bus, handlePing)
+			},
+			wantErrs: []string{"command unusedCommand: handler not registered"},
+		},
+		{
+			name: "handler for undeclared message",
+			configure: func(bus *Bus) {
+				RegisterQuery(bus, handlePingQuery)
+			},
+			wantErrs: []string{"handler registered for undeclared message pingQuery"},
+		},
+		{
+			name: "handler registered twice",
+			configure: func(bus *Bus) {
+				bus.DeclareCommands(&pingCommand{})
+				RegisterCommand(bus, handlePing)
+				RegisterCommand(bus, handlePing)
+			},
+			wantErrs: []string{"command pingCommand: handler registered more than once"},
+		},
+		{
+			name: "middleware after handlers",
+			configure: func(bus *Bus) {
+				bus.DeclareCommands(&pingCommand{})
+				RegisterCommand(bus, handlePing)
+				bus.Use(Tracing())
+			},
+			wantErrs: []string{"middleware must be added before handlers are registered"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			tt.configure(bus)
+			err := bus.Validate()
+			if err == nil {
+				t.Fatal("Validate succeeded, want error")
+			}
+			got := strings.Split(err.Error(), "\n")
+			if !slices.Equal(got, tt.wantErrs) {
+				t.Fatalf("Validate = %q, want %q", got, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestBusMiddlewareOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(info MessageInfo, next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, message any) (any, error) {
+				calls = append(calls, name+" "+string(info.Kind)+" "+info.Name)
+				return next(ctx, message)
+			}
+		}
+	}
+	bus := NewBus()
+	bus.Use(trace("first"), trace("second"))
+	bus.DeclareCommands(&pingCommand{})
+	RegisterCommand(bus, handlePing)
+
+	if _, err := Dispatch[*pingCommand, string](context.Background(), bus, &pingCommand{Value: "a"}); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	want := []string{"first command pingCommand", "second command pingCommand"}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+}
+
+// recordingUnitOfWork считает транзакции и возвращает ошибку коммита commitErr
+type recordingUnitOfWork struct {
+	transactions int
+	commitErr    error
+}
+
+func (u *recordingUnitOfWork) Within(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.transactions++
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return u.commitErr
+}
+
+func TestTransaction(t *testing.T) {
+	tests := []struct {
+		name       string
+		commitErr  error
+		handlerErr error
+		wantHooks  int
+		wantErr    bool
+	}{
+		{name: "hooks run after commit", wantHooks: 2},
+		{name: "hooks are dropped on handler error", handlerErr: errors.New("handler failed"), wantErr: true},
+		{name: "hooks are dropped on commit error", commitErr: errors.New("commit failed"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := &recordingUnitOfWork{commitErr: tt.commitErr}
+			hooks := 0
+			bus := NewBus()
+			bus.Use(Transaction(uow))
+			bus.DeclareCommands(&pingCommand{})
+			bus.DeclareQueries(&pingQuery{})
+			RegisterCommand(bus, func(ctx context.Context, command *pingCommand) (string, error) {
+				AfterCommit(ctx, func() { hooks++ })
+				// вложенная команда присоединяется к транзакции внешней
+				if command.Value == "outer" {
+					if _, err := Dispatch[*pingCommand, string](ctx, bus, &pingCommand{Value: "inner"}); err != nil {
+						return "", err
+					}
+				}
+				if hooks != 0 {
+					t.Error("hook ran before commit")
+				}
+				return "ok", tt.handlerErr
+			})
+			RegisterQuery(bus, handlePingQuery)
+
+			_, err := Dispatch[*pingCommand, string](context.Background(), bus, &pingCommand{Value: "outer"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if uow.transactions != 1 {
+				t.Fatalf("opened %d transactions, want 1", uow.transactions)
+			}
+			if hooks != tt.wantHooks {
+				t.Fatalf("ran %d hooks, want %d", hooks, tt.wantHooks)
+			}
+
+			if _, err := Ask[*pingQuery, int](context.Background(), bus, &pingQuery{}); err != nil {
+				t.Fatalf("Ask: %v", err)
+			}
+			if uow.transactions != 1 {
+				t.Fatal("query was run in a transaction")
+			}
+		})
+	}
+}
+
+func TestAfterCommitOutsideTransaction(t *testing.T) {
+	ran := false
+	AfterCommit(context.Background(), func() { ran = true })
+	if !ran {
+		t.Fatal("hook outside of a transaction was not run immediately")
+	}
+}
@@ -8,8 +8,8 @@ type Command interface {
 	GetAggregateID() int64
 }
 
-type CommandHandler[AbstractCommand Command] interface {
-	Handle(ctx context.Context, command AbstractCommand) (interface{}, error)
+type CommandHandler[AbstractCommand Command, Result any] interface {
+	Handle(ctx context.Context, command AbstractCommand) (Result, error)
 }
 
 type BaseCommand struct {
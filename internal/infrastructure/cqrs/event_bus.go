@@ -0,0 +1,81 @@
+package cqrs
+
+import (
+	"challenge-service/internal/infrastructure/lib/log"
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+type eventHandlerFunc func(ctx context.Context, event Event) error
+
+// EventBus внутрипроцессная шина событий. Подписчики вызываются синхронно в порядке подписки,
+// ошибки и паники подписчиков логируются и не влияют на остальных подписчиков и на издателя
+type EventBus struct {
+	log *slog.Logger
+
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]eventHandlerFunc
+	all      []eventHandlerFunc
+}
+
+func NewEventBus(log *slog.Logger) *EventBus {
+	return &EventBus{
+		log:      log,
+		handlers: make(map[reflect.Type][]eventHandlerFunc),
+	}
+}
+
+// Subscribe подписывает обработчик на события типа E
+func Subscribe[E Event](bus *EventBus, handler EventHandler[E]) {
+	var event E
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	eventType := reflect.TypeOf(event)
+	bus.handlers[eventType] = append(bus.handlers[eventType], func(ctx context.Context, event Event) error {
+		return handler.Handle(ctx, event.(E))
+	})
+}
+
+// SubscribeFunc подписывает функцию на события типа E
+func SubscribeFunc[E Event](bus *EventBus, handler func(ctx context.Context, event E) error) {
+	Subscribe[E](bus, eventHandlerAdapter[E](handler))
+}
+
+// SubscribeAll подписывает обработчик на все события, например для аудита
+func (b *EventBus) SubscribeAll(handler func(ctx context.Context, event Event) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, handler)
+}
+
+func (b *EventBus) Publish(ctx context.Context, events ...Event) {
+	for _, event := range events {
+		b.mu.RLock()
+		handlers := append(append([]eventHandlerFunc(nil), b.handlers[reflect.TypeOf(event)]...), b.all...)
+		b.mu.RUnlock()
+		for _, handler := range handlers {
+			if err := b.call(ctx, handler, event); err != nil {
+				b.log.Error("event handler failed", slog.String("event", event.EventName()),
+					slog.Int64("aggregate_id", event.GetAggregateID()), log.Err(err))
+			}
+		}
+	}
+}
+
+func (b *EventBus) call(ctx context.Context, handler eventHandlerFunc, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+type eventHandlerAdapter[E Event] func(ctx context.Context, event E) error
+
+func (f eventHandlerAdapter[E]) Handle(ctx context.Context, event E) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,38 @@
+package cqrs
+
+import (
+	"context"
+	"time"
+)
+
+type Event interface {
+	EventName() string
+	GetAggregateID() int64
+	GetOccurredAt() time.Time
+}
+
+type EventHandler[AbstractEvent Event] interface {
+	Handle(ctx context.Context, event AbstractEvent) error
+}
+
+// EventPublisher публикует события после успешного выполнения команды
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event)
+}
+
+type BaseEvent struct {
+	AggregateID int64     `json:"aggregate_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+func NewBaseEvent(aggregateID int64) BaseEvent {
+	return BaseEvent{AggregateID: aggregateID, OccurredAt: time.Now().UTC()}
+}
+
+func (e BaseEvent) GetAggregateID() int64 {
+	return e.AggregateID
+}
+
+func (e BaseEvent) GetOccurredAt() time.Time {
+	return e.OccurredAt
+}
@@ -0,0 +1,153 @@
+package cqrs
+
+import (
+	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/codes"
+	"log/slog"
+	"time"
+)
+
+// ErrValidation оборачивает ошибки проверки команд и запросов
+var ErrValidation = errors.New("validation failed")
+
+// Validator реализуют команды и запросы с проверками, которые не выражаются тегами
+type Validator interface {
+	Validate() error
+}
+
+// StructValidator декларативная проверка сообщения по тегам его полей
+type StructValidator interface {
+	Struct(value any) error
+}
+
+// DispatchObserver получает длительность и результат каждого выполнения команды или запроса
+type DispatchObserver interface {
+	ObserveCommand(name string, duration time.Duration, err error)
+	ObserveQuery(name string, duration time.Duration, err error)
+}
+
+// UnitOfWork выполняет fn в транзакции, которая передается репозиториям через контекст.
+// Вложенный вызов присоединяется к уже открытой транзакции
+type UnitOfWork interface {
+	Within(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Tracing выполняет обработчик в отдельном спане "command X" или "query X"
+func Tracing() Middleware {
+	return func(info MessageInfo, next HandlerFunc) HandlerFunc {
+		spanName := string(info.Kind) + " " + info.Name
+		return func(ctx context.Context, message any) (any, error) {
+			ctx, span := tracing.Tracer().Start(ctx, spanName)
+			defer span.End()
+			result, err := next(ctx, message)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return result, err
+		}
+	}
+}
+
+// Logging пишет выполнение обработчика в логгер запроса, а без него - в logger
+func Logging(logger *slog.Logger) Middleware {
+	return func(info MessageInfo, next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, message any) (any, error) {
+			started := time.Now()
+			result, err := next(ctx, message)
+			attrs := []any{
+				slog.String(string(info.Kind), info.Name),
+				slog.Duration("duration", time.Since(started)),
+			}
+			if err != nil {
+				log.FromContext(ctx, logger).Warn(string(info.Kind)+" failed", append(attrs, log.Err(err))...)
+			} else {
+				log.FromContext(ctx, logger).Info(string(info.Kind)+" handled", attrs...)
+			}
+			return result, err
+		}
+	}
+}
+
+// Metrics сообщает наблюдателю длительность и результат выполнения
+func Metrics(observer DispatchObserver) Middleware {
+	return func(info MessageInfo, next HandlerFunc) HandlerFunc {
+		observe := observer.ObserveQuery
+		if info.Kind == KindCommand {
+			observe = observer.ObserveCommand
+		}
+		return func(ctx context.Context, message any) (any, error) {
+			started := time.Now()
+			result, err := next(ctx, message)
+			observe(info.Name, time.Since(started), err)
+			return result, err
+		}
+	}
+}
+
+// Validation проверяет сообщение по тегам, затем вызывает Validate у сообщений, реализующих Validator.
+// Обработчик не вызывается, если сообщение невалидно
+func Validation(structValidator StructValidator) Middleware {
+	return func(info MessageInfo, next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, message any) (any, error) {
+			if err := structValidator.Struct(message); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+			}
+			if validator, ok := message.(Validator); ok {
+				if err := validator.Validate(); err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+				}
+			}
+			return next(ctx, message)
+		}
+	}
+}
+
+// Transaction выполняет каждую команду в одной транзакции. Функции, отложенные через AfterCommit,
+// вызываются только после успешного коммита. Запросы выполняются без транзакции
+func Transaction(uow UnitOfWork) Middleware {
+	return func(info MessageInfo, next HandlerFunc) HandlerFunc {
+		if info.Kind != KindCommand {
+			return next
+		}
+		return func(ctx context.Context, message any) (any, error) {
+			if _, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks); ok {
+				return next(ctx, message)
+			}
+			hooks := &afterCommitHooks{}
+			ctx = context.WithValue(ctx, afterCommitKey{}, hooks)
+			var result any
+			err := uow.Within(ctx, func(ctx context.Context) error {
+				var err error
+				result, err = next(ctx, message)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, hook := range hooks.fns {
+				hook()
+			}
+			return result, nil
+		}
+	}
+}
+
+type afterCommitKey struct{}
+
+type afterCommitHooks struct {
+	fns []func()
+}
+
+// AfterCommit откладывает fn до коммита транзакции команды, вне транзакции fn выполняется сразу
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks); ok {
+		hooks.fns = append(hooks.fns, fn)
+		return
+	}
+	fn()
+}
@@ -0,0 +1,65 @@
+package cqrs
+
+import (
+	"challenge-service/internal/infrastructure/lib/validation"
+	"context"
+	"errors"
+	"testing"
+)
+
+type renameCommand struct {
+	BaseCommand
+	Name    string `json:"name" validate:"required"`
+	OldName string `json:"old_name"`
+}
+
+// Validate правило, которое не выражается тегами
+func (c *renameCommand) Validate() error {
+	if c.Name == c.OldName {
+		return validation.NewError("name", validation.CodeInvalid, "", "must differ from old_name")
+	}
+	return nil
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     *renameCommand
+		wantField   string
+		wantHandled bool
+	}{
+		{name: "valid", command: &renameCommand{Name: "b", OldName: "a"}, wantHandled: true},
+		{name: "tag rule", command: &renameCommand{OldName: "a"}, wantField: "name"},
+		{name: "Validate method", command: &renameCommand{Name: "a", OldName: "a"}, wantField: "name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			bus := NewBus()
+			bus.Use(Validation(validation.New()))
+			bus.DeclareCommands(&renameCommand{})
+			RegisterCommand(bus, func(context.Context, *renameCommand) (bool, error) {
+				handled = true
+				return true, nil
+			})
+
+			_, err := Dispatch[*renameCommand, bool](context.Background(), bus, tt.command)
+			if handled != tt.wantHandled {
+				t.Fatalf("handled = %v, want %v", handled, tt.wantHandled)
+			}
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var fields validation.Errors
+			if !errors.Is(err, ErrValidation) || !errors.As(err, &fields) {
+				t.Fatalf("err = %v, want ErrValidation with field errors", err)
+			}
+			if len(fields) != 1 || fields[0].Field != tt.wantField {
+				t.Fatalf("fields = %+v, want one error for %s", fields, tt.wantField)
+			}
+		})
+	}
+}
@@ -8,8 +8,8 @@ type Query interface {
 	GetAggregateID() int64
 }
 
-type QueryHandler[AbstractQuery Query] interface {
-	Handle(ctx context.Context, query AbstractQuery) (interface{}, error)
+type QueryHandler[AbstractQuery Query, Result any] interface {
+	Handle(ctx context.Context, query AbstractQuery) (Result, error)
 }
 
 type BaseQuery struct {
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS authentication_participant;
+DROP TABLE IF EXISTS authentication_challenge;
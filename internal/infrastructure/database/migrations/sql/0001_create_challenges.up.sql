@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS authentication_challenge (
+    id               bigserial PRIMARY KEY,
+    name             varchar(255) NOT NULL,
+    icon             varchar(255) NOT NULL,
+    image            varchar(255) NOT NULL,
+    description      text         NOT NULL,
+    start_date       timestamptz  NOT NULL,
+    end_date         timestamptz  NOT NULL,
+    type             varchar(10)  NOT NULL,
+    is_team          boolean      NOT NULL,
+    is_finished      boolean      NOT NULL,
+    status           varchar(16)  NOT NULL DEFAULT 'draft',
+    creator_id       bigint       NOT NULL,
+    co_organizer_ids jsonb        NOT NULL DEFAULT '[]',
+    icon_variants    jsonb        NOT NULL DEFAULT '{}',
+    image_variants   jsonb        NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_status ON authentication_challenge (status);
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_creator_id ON authentication_challenge (creator_id);
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_start_date ON authentication_challenge (start_date, id);
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_end_date ON authentication_challenge (end_date, id);
+
+CREATE TABLE IF NOT EXISTS authentication_participant (
+    id           bigserial PRIMARY KEY,
+    status       varchar(10) NOT NULL,
+    progress     jsonb       NOT NULL,
+    achievement  text        NOT NULL,
+    challenge_id bigint      NOT NULL REFERENCES authentication_challenge (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    user_id      bigint      NOT NULL,
+    team_id      bigint      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_authentication_participant_challenge_id ON authentication_participant (challenge_id);
+CREATE INDEX IF NOT EXISTS idx_authentication_participant_user_id ON authentication_participant (user_id);
+CREATE INDEX IF NOT EXISTS idx_authentication_participant_team_id ON authentication_participant (team_id);
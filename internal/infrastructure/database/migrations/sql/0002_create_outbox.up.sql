@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id               bigserial    PRIMARY KEY,
+    event_id         uuid         NOT NULL,
+    aggregate_id     bigint       NOT NULL,
+    event_name       varchar(128) NOT NULL,
+    payload          jsonb        NOT NULL,
+    occurred_at      timestamptz  NOT NULL,
+    created_at       timestamptz  NOT NULL,
+    attempts         integer      NOT NULL DEFAULT 0,
+    next_attempt_at  timestamptz  NOT NULL,
+    last_error       text         NOT NULL DEFAULT '',
+    delivered_at     timestamptz,
+    dead_lettered_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_messages_event_id ON outbox_messages (event_id);
+-- relay выбирает только недоставленные сообщения, поэтому индекс частичный
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_pending ON outbox_messages (aggregate_id, id)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_next_attempt_at ON outbox_messages (next_attempt_at)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_authentication_challenge_search;
@@ -0,0 +1,7 @@
+-- выражение должно совпадать с challengeSearchVector в repository/challenge_search.go
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_search ON authentication_challenge USING gin ((
+    setweight(to_tsvector('russian', name), 'A') ||
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('russian', description), 'B') ||
+    setweight(to_tsvector('english', description), 'B')
+));
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_authentication_challenge_type;
+ALTER TABLE authentication_challenge DROP CONSTRAINT IF EXISTS chk_authentication_challenge_type;
+
+UPDATE authentication_challenge SET type = CASE type
+    WHEN 'family' THEN 'семейный'
+    WHEN 'personal' THEN 'личный'
+    WHEN 'group' THEN 'общий'
+    ELSE type
+END;
+
+ALTER TABLE authentication_challenge ALTER COLUMN type TYPE varchar(10);
@@ -0,0 +1,16 @@
+-- вид вызова хранится кодом вместо русского текста
+ALTER TABLE authentication_challenge ALTER COLUMN type TYPE varchar(16);
+
+UPDATE authentication_challenge SET type = CASE
+    WHEN lower(btrim(type)) IN ('family', 'personal', 'group') THEN lower(btrim(type))
+    WHEN lower(btrim(type)) LIKE 'сем%' THEN 'family'
+    WHEN lower(btrim(type)) LIKE 'лич%' THEN 'personal'
+    WHEN lower(btrim(type)) LIKE 'общ%' OR lower(btrim(type)) LIKE 'груп%' THEN 'group'
+    -- нераспознанные значения определяются по формату участия
+    WHEN is_team THEN 'group'
+    ELSE 'personal'
+END;
+
+ALTER TABLE authentication_challenge
+    ADD CONSTRAINT chk_authentication_challenge_type CHECK (type IN ('family', 'personal', 'group'));
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_type ON authentication_challenge (type);
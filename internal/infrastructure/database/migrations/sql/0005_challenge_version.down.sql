@@ -0,0 +1 @@
+ALTER TABLE authentication_challenge DROP COLUMN IF EXISTS version;
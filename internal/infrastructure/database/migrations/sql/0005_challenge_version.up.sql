@@ -0,0 +1,2 @@
+-- версия для оптимистичной блокировки изменений вызова
+ALTER TABLE authentication_challenge ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- без deleted_at вызовы из корзины снова стали бы видимыми, поэтому они удаляются окончательно
+DELETE FROM authentication_participant
+    WHERE challenge_id IN (SELECT id FROM authentication_challenge WHERE deleted_at IS NOT NULL);
+DELETE FROM authentication_challenge WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_authentication_challenge_deleted_at;
+ALTER TABLE authentication_challenge DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+ALTER TABLE authentication_challenge ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+-- корзина и очистка выбирают только удаленные вызовы, поэтому индекс частичный
+CREATE INDEX IF NOT EXISTS idx_authentication_challenge_deleted_at ON authentication_challenge (creator_id, deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,8 @@
+-- отправленные напоминания "осталось дней": общая для всех экземпляров отметка,
+-- чтобы напоминание уходило один раз и не повторялось после перезапуска
+CREATE TABLE IF NOT EXISTS challenge_reminders (
+    challenge_id bigint      NOT NULL REFERENCES authentication_challenge (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    days_left    integer     NOT NULL,
+    sent_at      timestamptz NOT NULL,
+    PRIMARY KEY (challenge_id, days_left)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE authentication_participant DROP CONSTRAINT IF EXISTS authentication_participant_challenge_id_fkey;
+ALTER TABLE authentication_participant ADD CONSTRAINT authentication_participant_challenge_id_fkey
+    FOREIGN KEY (challenge_id) REFERENCES authentication_challenge (id) ON UPDATE CASCADE ON DELETE SET NULL;
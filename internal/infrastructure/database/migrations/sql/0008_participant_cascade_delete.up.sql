@@ -0,0 +1,9 @@
+-- challenge_id NOT NULL, поэтому ON DELETE SET NULL из 0001 не мог выполниться. Окончательная очистка
+-- корзины удаляет участников вместе с вызовом, поэтому ключ пересоздается с ON DELETE CASCADE.
+-- Базы, созданные через AutoMigrate, содержат тот же ключ под именем fk_authentication_participant_challenge
+DELETE FROM authentication_participant p
+    WHERE NOT EXISTS (SELECT 1 FROM authentication_challenge c WHERE c.id = p.challenge_id);
+ALTER TABLE authentication_participant DROP CONSTRAINT IF EXISTS authentication_participant_challenge_id_fkey;
+ALTER TABLE authentication_participant DROP CONSTRAINT IF EXISTS fk_authentication_participant_challenge;
+ALTER TABLE authentication_participant ADD CONSTRAINT authentication_participant_challenge_id_fkey
+    FOREIGN KEY (challenge_id) REFERENCES authentication_challenge (id) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_authentication_participant_challenge_team;
+DROP INDEX IF EXISTS idx_authentication_participant_challenge_user;
@@ -0,0 +1,7 @@
+-- повторная регистрация проверяется до вставки, индексы не дают параллельным запросам создать дубликат.
+-- У индивидуальных участников team_id = 0, у командных user_id = 0. Если дубликаты уже есть,
+-- миграция завершится ошибкой и их нужно удалить вручную
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authentication_participant_challenge_user
+    ON authentication_participant (challenge_id, user_id) WHERE team_id = 0;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authentication_participant_challenge_team
+    ON authentication_participant (challenge_id, team_id) WHERE team_id <> 0;
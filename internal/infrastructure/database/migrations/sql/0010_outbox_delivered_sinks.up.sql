@@ -0,0 +1,3 @@
+-- приемники, уже принявшие сообщение: при повторе из-за сбоя другого приемника им оно не отправляется,
+-- поэтому уведомления не дублируются
+ALTER TABLE outbox_messages ADD COLUMN IF NOT EXISTS delivered_sinks jsonb NOT NULL DEFAULT '[]';
@@ -10,7 +10,6 @@ import (
 
 type PostgresConnectable interface {
 	database.DatabaseConnectable
-	CreateTables(db *gorm.DB, models ...interface{}) error
 }
 
 type postgresConnect struct {
@@ -42,14 +41,3 @@ func (p *postgresConnect) CloseConnection(inputedDb interface{}) error {
 	}
 	return dbClient.Close()
 }
-
-// CreateTables создает таблицы в базе данных
-func (p *postgresConnect) CreateTables(db *gorm.DB, models ...interface{}) error {
-	for _, model := range models {
-		err := db.Migrator().CreateTable(&model)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
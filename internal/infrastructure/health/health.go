@@ -0,0 +1,119 @@
+// Package health проверки зависимостей сервиса для liveness и readiness проб.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusFail     Status = "fail"
+)
+
+// Check проверка одной зависимости. Сбой необязательной (Optional) зависимости
+// переводит сервис в состояние degraded, обязательной - в fail
+type Check struct {
+	Name     string
+	Optional bool
+	Timeout  time.Duration
+	Func     func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Optional  bool    `json:"optional"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type Checker struct {
+	timeout time.Duration
+	checks  []Check
+}
+
+// NewChecker создает набор проверок. timeout используется для проверок без собственного таймаута
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{timeout: timeout, checks: checks}
+}
+
+func (c *Checker) Add(checks ...Check) {
+	c.checks = append(c.checks, checks...)
+}
+
+// Run выполняет все проверки параллельно, каждую со своим таймаутом
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		switch {
+		case result.Status == StatusOK:
+		case result.Optional:
+			if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		default:
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- check.Func(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := CheckResult{
+		Name:      check.Name,
+		Status:    StatusOK,
+		Optional:  check.Optional,
+		LatencyMS: float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval не дает перезапрашивать JWKS чаще раза в минуту при неизвестном kid
+// или недоступном JWKS
+const minRefreshInterval = time.Minute
+
+var ErrKeyNotFound = errors.New("signing key not found in JWKS")
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// KeySet набор публичных ключей из локального JWKS файла или по URL
+type KeySet struct {
+	load            func(ctx context.Context) ([]byte, error)
+	refreshInterval time.Duration
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+	// fetchedAt время последней успешной загрузки, attemptedAt - последней попытки, в том числе неудачной
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	loadErr     error
+}
+
+// NewFileKeySet читает JWKS из файла один раз при старте
+func NewFileKeySet(path string) (*KeySet, error) {
+	keySet := &KeySet{
+		load: func(ctx context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+	if err := keySet.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	return keySet, nil
+}
+
+// NewURLKeySet загружает JWKS по URL лениво и обновляет его раз в refreshInterval
+func NewURLKeySet(url string, refreshInterval time.Duration, client *http.Client) *KeySet {
+	return &KeySet{
+		refreshInterval: refreshInterval,
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("jwks endpoint returned %d", resp.StatusCode)
+			}
+			return io.ReadAll(resp.Body)
+		},
+	}
+}
+
+// Key возвращает ключ по kid. Если kid пуст, а в наборе один ключ, возвращается он.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	key, found := k.lookup(kid)
+	stale := k.refreshInterval > 0 && time.Since(k.fetchedAt) > k.refreshInterval
+	k.mu.RUnlock()
+
+	if found && !stale {
+		return key, nil
+	}
+	if !k.claimRefresh() {
+		if found {
+			return key, nil
+		}
+		k.mu.RLock()
+		defer k.mu.RUnlock()
+		if k.fetchedAt.IsZero() && k.loadErr != nil {
+			return nil, k.loadErr
+		}
+		return nil, ErrKeyNotFound
+	}
+	if err := k.refresh(ctx); err != nil {
+		if found {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if key, found = k.lookup(kid); !found {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// claimRefresh отмечает попытку обновления, если предыдущая была не раньше minRefreshInterval назад.
+// Неудачные попытки тоже учитываются, поэтому недоступный JWKS не запрашивается на каждый токен
+func (k *KeySet) claimRefresh() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.refreshInterval <= 0 || time.Since(k.attemptedAt) < minRefreshInterval {
+		return false
+	}
+	k.attemptedAt = time.Now()
+	return true
+}
+
+func (k *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func (k *KeySet) refresh(ctx context.Context) error {
+	keys, err := k.loadKeys(ctx)
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.loadErr = err
+	if err != nil {
+		return err
+	}
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	return nil
+}
+
+// loadKeys загружает JWKS и разбирает ключи подписи
+func (k *KeySet) loadKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := k.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS: %w", err)
+	}
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"challenge-service/config"
+	"context"
+	"errors"
+	"slices"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleSystem = "system"
+)
+
+// Principal аутентифицированный пользователь, извлеченный из JWT
+type Principal struct {
+	UserID  int64    `json:"user_id"`
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+	TeamIDs []int64  `json:"team_ids"`
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// IsTeamMember пользователь состоит в команде teamID по данным токена
+func (p *Principal) IsTeamMember(teamID int64) bool {
+	return slices.Contains(p.TeamIDs, teamID)
+}
+
+// SystemPrincipal пользователь для фоновых задач сервиса
+func SystemPrincipal() *Principal {
+	return &Principal{Subject: "challenge-service", Roles: []string{RoleSystem}}
+}
+
+// DevPrincipal пользователь из auth.devPrincipal для работы с выключенной проверкой токенов.
+// Без явно заданного пользователя политики отклоняли бы все команды, поэтому такая настройка ошибочна
+func DevPrincipal(cfg config.AuthConfig) (*Principal, error) {
+	if cfg.DevPrincipal.UserID <= 0 {
+		return nil, errors.New("auth.enabled=false requires auth.devPrincipal.userID")
+	}
+	return &Principal{
+		UserID:  cfg.DevPrincipal.UserID,
+		Subject: "dev",
+		Roles:   cfg.DevPrincipal.Roles,
+		TeamIDs: cfg.DevPrincipal.TeamIDs,
+	}, nil
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext возвращает пользователя запроса, если он был аутентифицирован
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
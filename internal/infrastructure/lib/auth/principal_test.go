@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"challenge-service/config"
+	"slices"
+	"testing"
+)
+
+func TestDevPrincipal(t *testing.T) {
+	tests := []struct {
+		name    string
+		dev     config.DevPrincipal
+		wantErr bool
+	}{
+		{name: "configured", dev: config.DevPrincipal{UserID: 7, Roles: []string{RoleAdmin}, TeamIDs: []int64{3}}},
+		{name: "not configured", wantErr: true},
+		{name: "invalid user", dev: config.DevPrincipal{UserID: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := DevPrincipal(config.AuthConfig{DevPrincipal: tt.dev})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DevPrincipal = %+v, want error", principal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DevPrincipal: %v", err)
+			}
+			if principal.UserID != tt.dev.UserID || !slices.Equal(principal.Roles, tt.dev.Roles) ||
+				!slices.Equal(principal.TeamIDs, tt.dev.TeamIDs) {
+				t.Fatalf("principal = %+v, want %+v", principal, tt.dev)
+			}
+		})
+	}
+}
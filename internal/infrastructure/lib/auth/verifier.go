@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"challenge-service/config"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jwt "github.com/golang-jwt/jwt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Verifier проверяет подпись и claims JWT и собирает из них Principal
+type Verifier struct {
+	cfg    config.AuthConfig
+	secret []byte
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(cfg *config.Config) (*Verifier, error) {
+	verifier := &Verifier{
+		cfg:    cfg.Auth,
+		secret: []byte(cfg.SecretKey),
+		parser: &jwt.Parser{ValidMethods: cfg.Auth.Algorithms, UseJSONNumber: true},
+	}
+
+	needsKeys := false
+	for _, algorithm := range cfg.Auth.Algorithms {
+		switch algorithm {
+		case AlgorithmHS256:
+			if len(verifier.secret) == 0 {
+				return nil, errors.New("HS256 requires secretKey")
+			}
+		case AlgorithmRS256, AlgorithmES256:
+			needsKeys = true
+		default:
+			return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+		}
+	}
+	if !needsKeys {
+		return verifier, nil
+	}
+
+	switch {
+	case cfg.Auth.JWKSFile != "":
+		keys, err := NewFileKeySet(cfg.Auth.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = keys
+	case cfg.Auth.JWKSURL != "":
+		verifier.keys = NewURLKeySet(cfg.Auth.JWKSURL, cfg.Auth.JWKSRefreshInterval,
+			&http.Client{Timeout: 10 * time.Second})
+	default:
+		return nil, errors.New("RS256/ES256 require jwksFile or jwksURL")
+	}
+	return verifier, nil
+}
+
+// Verify проверяет токен и возвращает пользователя
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	token, err := v.parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return v.secret, nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			if v.keys == nil {
+				return nil, jwt.ErrInvalidKey
+			}
+			kid, _ := token.Header["kid"].(string)
+			key, err := v.keys.Key(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case *rsa.PublicKey:
+				if _, ok := token.Method.(*jwt.SigningMethodRSA); ok {
+					return key, nil
+				}
+			case *ecdsa.PublicKey:
+				if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
+					return key, nil
+				}
+			}
+			return nil, jwt.ErrInvalidKeyType
+		}
+		return nil, jwt.ErrInvalidKey
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("%w: invalid claims", ErrInvalidToken)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("%w: token has expired", ErrInvalidToken)
+	}
+	if v.cfg.Issuer != "" && !claims.VerifyIssuer(v.cfg.Issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.cfg.Audience != "" && !claims.VerifyAudience(v.cfg.Audience, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return principalFromClaims(claims, v.cfg.TeamsClaim)
+}
+
+func principalFromClaims(claims jwt.MapClaims, teamsClaim string) (*Principal, error) {
+	principal := &Principal{}
+	principal.Subject, _ = claims["sub"].(string)
+
+	userID, ok := claims["user_id"]
+	if !ok {
+		userID = principal.Subject
+	}
+	id, err := parseID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+	principal.UserID = id
+
+	switch roles := claims["roles"].(type) {
+	case []interface{}:
+		for _, role := range roles {
+			if name, ok := role.(string); ok {
+				principal.Roles = append(principal.Roles, name)
+			}
+		}
+	case string:
+		principal.Roles = append(principal.Roles, roles)
+	}
+	if role, ok := claims["role"].(string); ok {
+		principal.Roles = append(principal.Roles, role)
+	}
+	// роль system выдается только внутренним задачам сервиса
+	principal.Roles = slices.DeleteFunc(principal.Roles, func(role string) bool {
+		return role == RoleSystem
+	})
+
+	if teams, ok := claims[teamsClaim].([]interface{}); ok {
+		for _, team := range teams {
+			teamID, err := parseID(team)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid %s claim: %s", ErrInvalidToken, teamsClaim, err)
+			}
+			principal.TeamIDs = append(principal.TeamIDs, teamID)
+		}
+	}
+	return principal, nil
+}
+
+func parseID(value interface{}) (int64, error) {
+	switch id := value.(type) {
+	case json.Number:
+		return id.Int64()
+	case float64:
+		return int64(id), nil
+	case string:
+		return strconv.ParseInt(id, 10, 64)
+	}
+	return 0, errors.New("id claim is missing or not a number")
+}
@@ -0,0 +1,397 @@
+package auth
+
+import (
+	"challenge-service/config"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	jwt "github.com/golang-jwt/jwt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testSecret = "secret"
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// validClaims claims, которые проходят проверку, с заменой полей из overrides (nil удаляет поле)
+func validClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"sub":      "42",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+		"iss":      "auth-service",
+		"aud":      "challenge-service",
+		"roles":    []string{"user"},
+		"team_ids": []int64{7, 8},
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+func newTestVerifier(t *testing.T, auth config.AuthConfig) *Verifier {
+	t.Helper()
+	if auth.TeamsClaim == "" {
+		auth.TeamsClaim = "team_ids"
+	}
+	verifier, err := NewVerifier(&config.Config{SecretKey: testSecret, Auth: auth})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	return verifier
+}
+
+func TestVerifierHS256(t *testing.T) {
+	verifier := newTestVerifier(t, config.AuthConfig{
+		Algorithms: []string{AlgorithmHS256},
+		Issuer:     "auth-service",
+		Audience:   "challenge-service",
+	})
+	hs256 := func(t *testing.T, claims jwt.MapClaims) string {
+		return sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", claims)
+	}
+
+	tests := []struct {
+		name      string
+		token     func(t *testing.T) string
+		want      *Principal
+		wantError bool
+	}{
+		{
+			name:  "valid token",
+			token: func(t *testing.T) string { return hs256(t, validClaims(nil)) },
+			want:  &Principal{UserID: 42, Subject: "42", Roles: []string{"user"}, TeamIDs: []int64{7, 8}},
+		},
+		{
+			name: "user_id claim takes precedence over sub",
+			token: func(t *testing.T) string {
+				return hs256(t, validClaims(jwt.MapClaims{"sub": "alice", "user_id": 5, "roles": nil, "team_ids": nil}))
+			},
+			want: &Principal{UserID: 5, Subject: "alice"},
+		},
+		{
+			name: "single role claims are merged",
+			token: func(t *testing.T) string {
+				return hs256(t, validClaims(jwt.MapClaims{"roles": "user", "role": RoleAdmin, "team_ids": nil}))
+			},
+			want: &Principal{UserID: 42, Subject: "42", Roles: []string{"user", RoleAdmin}},
+		},
+		{
+			name: "system role is stripped",
+			token: func(t *testing.T) string {
+				return hs256(t, validClaims(jwt.MapClaims{"roles": []string{RoleSystem, RoleAdmin}, "role": RoleSystem,
+					"team_ids": nil}))
+			},
+			want: &Principal{UserID: 42, Subject: "42", Roles: []string{RoleAdmin}},
+		},
+		{
+			name: "team ids as strings",
+			token: func(t *testing.T) string {
+				return hs256(t, validClaims(jwt.MapClaims{"roles": nil, "team_ids": []string{"9"}}))
+			},
+			want: &Principal{UserID: 42, Subject: "42", TeamIDs: []int64{9}},
+		},
+		{
+			name: "invalid team id",
+			token: func(t *testing.T) string {
+				return hs256(t, validClaims(jwt.MapClaims{"team_ids": []string{"red"}}))
+			},
+			wantError: true,
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return hs256(t, validClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))
+			},
+			wantError: true,
+		},
+		{
+			name:      "without expiration",
+			token:     func(t *testing.T) string { return hs256(t, validClaims(jwt.MapClaims{"exp": nil})) },
+			wantError: true,
+		},
+		{
+			name:      "unexpected issuer",
+			token:     func(t *testing.T) string { return hs256(t, validClaims(jwt.MapClaims{"iss": "someone-else"})) },
+			wantError: true,
+		},
+		{
+			name:      "unexpected audience",
+			token:     func(t *testing.T) string { return hs256(t, validClaims(jwt.MapClaims{"aud": "other-service"})) },
+			wantError: true,
+		},
+		{
+			name:      "user id is not a number",
+			token:     func(t *testing.T) string { return hs256(t, validClaims(jwt.MapClaims{"sub": "alice"})) },
+			wantError: true,
+		},
+		{
+			name: "wrong secret",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, []byte("other"), "", validClaims(nil))
+			},
+			wantError: true,
+		},
+		{
+			name: "algorithm is not allowed",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS512, []byte(testSecret), "", validClaims(nil))
+			},
+			wantError: true,
+		},
+		{
+			name:      "malformed",
+			token:     func(*testing.T) string { return "not-a-token" },
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token(t))
+			if tt.wantError {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("err = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.UserID != tt.want.UserID || principal.Subject != tt.want.Subject ||
+				!slices.Equal(principal.Roles, tt.want.Roles) || !slices.Equal(principal.TeamIDs, tt.want.TeamIDs) {
+				t.Fatalf("principal = %+v, want %+v", principal, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewVerifierConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		auth   config.AuthConfig
+	}{
+		{name: "HS256 without secret", auth: config.AuthConfig{Algorithms: []string{AlgorithmHS256}}},
+		{name: "unsupported algorithm", secret: testSecret, auth: config.AuthConfig{Algorithms: []string{"none"}}},
+		{name: "RS256 without JWKS", auth: config.AuthConfig{Algorithms: []string{AlgorithmRS256}}},
+		{name: "missing JWKS file", auth: config.AuthConfig{Algorithms: []string{AlgorithmES256},
+			JWKSFile: filepath.Join(t.TempDir(), "missing.json")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVerifier(&config.Config{SecretKey: tt.secret, Auth: tt.auth}); err == nil {
+				t.Fatal("NewVerifier succeeded, want error")
+			}
+		})
+	}
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{Kty: "RSA", Kid: kid, Use: "sig", N: encodeBigInt(key.N), E: encodeBigInt(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) jsonWebKey {
+	return jsonWebKey{Kty: "EC", Kid: kid, Crv: "P-256", X: encodeBigInt(key.X), Y: encodeBigInt(key.Y)}
+}
+
+func TestVerifierJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_ = json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{
+			rsaJWK("rsa-1", &rsaKey.PublicKey),
+			ecJWK("ec-1", &ecKey.PublicKey),
+			{Kty: "RSA", Kid: "enc-1", Use: "enc", N: "AQAB", E: "AQAB"},
+		}})
+	}))
+	t.Cleanup(server.Close)
+	verifier := newTestVerifier(t, config.AuthConfig{
+		Algorithms:          []string{AlgorithmRS256, AlgorithmES256},
+		JWKSURL:             server.URL,
+		JWKSRefreshInterval: time.Hour,
+	})
+
+	tests := []struct {
+		name      string
+		token     func(t *testing.T) string
+		wantError bool
+	}{
+		{
+			name:  "RS256",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", validClaims(nil)) },
+		},
+		{
+			name:  "ES256",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodES256, ecKey, "ec-1", validClaims(nil)) },
+		},
+		{
+			name: "signed by another key",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodRS256, otherRSAKey, "rsa-1", validClaims(nil))
+			},
+			wantError: true,
+		},
+		{
+			name: "kid of a key of another type",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodRS256, rsaKey, "ec-1", validClaims(nil))
+			},
+			wantError: true,
+		},
+		{
+			name:      "unknown kid",
+			token:     func(t *testing.T) string { return sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-2", validClaims(nil)) },
+			wantError: true,
+		},
+		{
+			name:      "encryption key is ignored",
+			token:     func(t *testing.T) string { return sign(t, jwt.SigningMethodRS256, rsaKey, "enc-1", validClaims(nil)) },
+			wantError: true,
+		},
+		{
+			name:      "kid is required when the set has several keys",
+			token:     func(t *testing.T) string { return sign(t, jwt.SigningMethodRS256, rsaKey, "", validClaims(nil)) },
+			wantError: true,
+		},
+		{
+			name: "HS256 is not allowed",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims(nil))
+			},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token(t))
+			if tt.wantError {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("err = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.UserID != 42 {
+				t.Fatalf("UserID = %d, want 42", principal.UserID)
+			}
+		})
+	}
+	// неизвестные kid не должны перезапрашивать JWKS чаще minRefreshInterval
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("JWKS was fetched %d times, want 1", got)
+	}
+}
+
+func TestURLKeySetUnavailable(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	keySet := NewURLKeySet(server.URL, time.Hour, server.Client())
+
+	for range 3 {
+		if _, err := keySet.Key(context.Background(), "rsa-1"); err == nil {
+			t.Fatal("Key succeeded, want error")
+		}
+	}
+	// неудачная загрузка тоже не повторяется чаще minRefreshInterval
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("JWKS was fetched %d times, want 1", got)
+	}
+}
+
+func TestFileKeySet(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	write := func(t *testing.T, set jsonWebKeySet) string {
+		t.Helper()
+		data, err := json.Marshal(set)
+		if err != nil {
+			t.Fatalf("marshal JWKS: %v", err)
+		}
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("write JWKS: %v", err)
+		}
+		return path
+	}
+
+	t.Run("single key without kid", func(t *testing.T) {
+		verifier := newTestVerifier(t, config.AuthConfig{
+			Algorithms: []string{AlgorithmES256},
+			JWKSFile:   write(t, jsonWebKeySet{Keys: []jsonWebKey{ecJWK("ec-1", &ecKey.PublicKey)}}),
+		})
+		token := sign(t, jwt.SigningMethodES256, ecKey, "", validClaims(nil))
+		if _, err := verifier.Verify(context.Background(), token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	invalid := []struct {
+		name string
+		key  jsonWebKey
+	}{
+		{name: "unsupported key type", key: jsonWebKey{Kty: "oct", Kid: "k"}},
+		{name: "unsupported curve", key: jsonWebKey{Kty: "EC", Kid: "k", Crv: "P-192"}},
+		{name: "point is not on curve", key: jsonWebKey{Kty: "EC", Kid: "k", Crv: "P-256", X: "AQ", Y: "AQ"}},
+		{name: "malformed modulus", key: jsonWebKey{Kty: "RSA", Kid: "k", N: "***", E: "AQAB"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFileKeySet(write(t, jsonWebKeySet{Keys: []jsonWebKey{tt.key}})); err == nil {
+				t.Fatal("NewFileKeySet succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Package imaging проверка загружаемых изображений и подготовка их вариантов фиксированного размера.
+package imaging
+
+import (
+	"bytes"
+	"challenge-service/config"
+	"errors"
+	"fmt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"slices"
+)
+
+var (
+	ErrTooLarge        = errors.New("image is too large")
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrInvalidImage    = errors.New("invalid image")
+)
+
+// Variant вариант изображения: исходник масштабируется и обрезается по центру до Width x Height
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var (
+	IconVariants = []Variant{
+		{Name: "64", Width: 64, Height: 64},
+		{Name: "128", Width: 128, Height: 128},
+	}
+	ImageVariants = []Variant{
+		{Name: "card", Width: 640, Height: 360},
+		{Name: "hero", Width: 1600, Height: 900},
+	}
+)
+
+// Encoded перекодированное изображение без метаданных
+type Encoded struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+type Result struct {
+	Original Encoded
+	Variants map[string]Encoded
+}
+
+type Processor struct {
+	cfg config.ImagesConfig
+}
+
+func NewProcessor(cfg config.ImagesConfig) *Processor {
+	return &Processor{cfg: cfg}
+}
+
+// MaxSize максимальный размер загружаемого файла в байтах
+func (p *Processor) MaxSize() int64 {
+	return p.cfg.MaxSize
+}
+
+// Process проверяет размер и тип файла по содержимому, декодирует его, поворачивает по EXIF
+// и перекодирует исходник и варианты. EXIF и прочие метаданные в результат не попадают.
+func (p *Processor) Process(data []byte, variants []Variant) (*Result, error) {
+	if int64(len(data)) > p.cfg.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrTooLarge, len(data), p.cfg.MaxSize)
+	}
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(p.cfg.AllowedTypes, contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, err)
+	}
+	if imageConfig.Width <= 0 || imageConfig.Height <= 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrInvalidImage)
+	}
+	if imageConfig.Width*imageConfig.Height > p.cfg.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels", ErrTooLarge, imageConfig.Width, imageConfig.Height)
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, err)
+	}
+
+	src := toNRGBA(decoded)
+	if contentType == "image/jpeg" {
+		src = applyOrientation(src, jpegOrientation(data))
+	}
+	opaque := src.Opaque()
+
+	result := &Result{Variants: make(map[string]Encoded, len(variants))}
+	result.Original, err = p.encode(fit(src, p.cfg.MaxDimension), opaque)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		encoded, err := p.encode(cover(src, variant.Width, variant.Height), opaque)
+		if err != nil {
+			return nil, err
+		}
+		result.Variants[variant.Name] = encoded
+	}
+	return result, nil
+}
+
+// encode сохраняет непрозрачные изображения в JPEG, с прозрачностью - в PNG
+func (p *Processor) encode(img image.Image, opaque bool) (Encoded, error) {
+	var buf bytes.Buffer
+	if opaque {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.cfg.JPEGQuality}); err != nil {
+			return Encoded{}, err
+		}
+		return Encoded{Data: buf.Bytes(), ContentType: "image/jpeg", Ext: ".jpg"}, nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return Encoded{}, err
+	}
+	return Encoded{Data: buf.Bytes(), ContentType: "image/png", Ext: ".png"}, nil
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+		return nrgba
+	}
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// fit уменьшает изображение так, чтобы большая сторона не превышала maxDimension
+func fit(src *image.NRGBA, maxDimension int) image.Image {
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		return src
+	}
+	if width >= height {
+		height = max(height*maxDimension/width, 1)
+		width = maxDimension
+	} else {
+		width = max(width*maxDimension/height, 1)
+		height = maxDimension
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// cover масштабирует изображение до заполнения width x height и обрезает лишнее по центру
+func cover(src *image.NRGBA, width int, height int) image.Image {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	crop := src.Bounds()
+	if srcWidth*height > srcHeight*width {
+		cropWidth := srcHeight * width / height
+		crop.Min.X = (srcWidth - cropWidth) / 2
+		crop.Max.X = crop.Min.X + cropWidth
+	} else {
+		cropHeight := srcWidth * height / width
+		crop.Min.Y = (srcHeight - cropHeight) / 2
+		crop.Max.Y = crop.Min.Y + cropHeight
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
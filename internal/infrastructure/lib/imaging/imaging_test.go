@@ -0,0 +1,332 @@
+package imaging
+
+import (
+	"bytes"
+	"challenge-service/config"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+var (
+	red   = color.NRGBA{R: 255, A: 255}
+	green = color.NRGBA{G: 255, A: 255}
+	blue  = color.NRGBA{B: 255, A: 255}
+	white = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+func testProcessor(cfg config.ImagesConfig) *Processor {
+	defaults := config.ImagesConfig{
+		MaxSize:      1 << 20,
+		MaxPixels:    1 << 22,
+		MaxDimension: 2560,
+		AllowedTypes: []string{"image/jpeg", "image/png", "image/webp"},
+		JPEGQuality:  100,
+	}
+	if cfg.MaxSize != 0 {
+		defaults.MaxSize = cfg.MaxSize
+	}
+	if cfg.MaxPixels != 0 {
+		defaults.MaxPixels = cfg.MaxPixels
+	}
+	if cfg.MaxDimension != 0 {
+		defaults.MaxDimension = cfg.MaxDimension
+	}
+	if cfg.AllowedTypes != nil {
+		defaults.AllowedTypes = cfg.AllowedTypes
+	}
+	return NewProcessor(defaults)
+}
+
+// quadrants изображение width x height из четырех одноцветных четвертей:
+// красная, зеленая сверху и синяя, белая снизу
+func quadrants(width int, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := red
+			switch {
+			case x >= width/2 && y < height/2:
+				c = green
+			case x < width/2 && y >= height/2:
+				c = blue
+			case x >= width/2 && y >= height/2:
+				c = white
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withEXIF вставляет после SOI сегмент APP1 с тегом Orientation и строкой comment
+func withEXIF(data []byte, order binary.ByteOrder, orientation uint16, comment string) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifOrientationTag)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	tiff = append(tiff, comment...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+func decode(t *testing.T, encoded Encoded) image.Image {
+	t.Helper()
+	img, _, err := image.Decode(bytes.NewReader(encoded.Data))
+	if err != nil {
+		t.Fatalf("decode %s: %v", encoded.ContentType, err)
+	}
+	return img
+}
+
+// near цвета совпадают с точностью до потерь JPEG
+func near(got color.Color, want color.NRGBA) bool {
+	r, g, b, _ := got.RGBA()
+	diff := func(a uint32, b uint8) bool {
+		d := int(a>>8) - int(b)
+		return d > -48 && d < 48
+	}
+	return diff(r, want.R) && diff(g, want.G) && diff(b, want.B)
+}
+
+func TestProcessContentType(t *testing.T) {
+	var gifData bytes.Buffer
+	if err := gif.Encode(&gifData, quadrants(8, 8), nil); err != nil {
+		t.Fatalf("gif.Encode: %v", err)
+	}
+	pngData := encodePNG(t, quadrants(8, 8))
+
+	tests := []struct {
+		name    string
+		allowed []string
+		data    []byte
+		wantErr error
+	}{
+		{name: "png is allowed", data: pngData},
+		{name: "jpeg is allowed", data: encodeJPEG(t, quadrants(8, 8))},
+		{name: "gif is not in the allowlist", data: gifData.Bytes(), wantErr: ErrUnsupportedType},
+		{name: "type is detected by content, not by name", data: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"),
+			wantErr: ErrUnsupportedType},
+		{name: "allowlist from config", allowed: []string{"image/jpeg"}, data: pngData, wantErr: ErrUnsupportedType},
+		{name: "truncated image", data: pngData[:len(pngData)/2], wantErr: ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testProcessor(config.ImagesConfig{AllowedTypes: tt.allowed}).Process(tt.data, IconVariants)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessLimits(t *testing.T) {
+	data := encodePNG(t, quadrants(100, 50))
+	tests := []struct {
+		name    string
+		cfg     config.ImagesConfig
+		wantErr error
+	}{
+		{name: "within limits", cfg: config.ImagesConfig{MaxPixels: 5000}},
+		{name: "too many pixels", cfg: config.ImagesConfig{MaxPixels: 4999}, wantErr: ErrTooLarge},
+		{name: "file too large", cfg: config.ImagesConfig{MaxSize: int64(len(data) - 1)}, wantErr: ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testProcessor(tt.cfg).Process(data, nil)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessOrientation(t *testing.T) {
+	// исходник 40x20: красная, зеленая четверти сверху, синяя, белая снизу.
+	// want - цвета углов после поворота в порядке: левый верхний, правый верхний, левый нижний, правый нижний
+	tests := []struct {
+		orientation uint16
+		wantRotated bool
+		want        [4]color.NRGBA
+	}{
+		{orientation: 1, want: [4]color.NRGBA{red, green, blue, white}},
+		{orientation: 2, want: [4]color.NRGBA{green, red, white, blue}},
+		{orientation: 3, want: [4]color.NRGBA{white, blue, green, red}},
+		{orientation: 4, want: [4]color.NRGBA{blue, white, red, green}},
+		{orientation: 5, wantRotated: true, want: [4]color.NRGBA{red, blue, green, white}},
+		{orientation: 6, wantRotated: true, want: [4]color.NRGBA{blue, red, white, green}},
+		{orientation: 7, wantRotated: true, want: [4]color.NRGBA{white, green, blue, red}},
+		{orientation: 8, wantRotated: true, want: [4]color.NRGBA{green, white, red, blue}},
+	}
+	source := encodeJPEG(t, quadrants(40, 20))
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		for _, tt := range tests {
+			t.Run(order.String()+"/"+string(rune('0'+tt.orientation)), func(t *testing.T) {
+				data := withEXIF(source, order, tt.orientation, "")
+				if got := jpegOrientation(data); got != int(tt.orientation) {
+					t.Fatalf("jpegOrientation = %d, want %d", got, tt.orientation)
+				}
+				result, err := testProcessor(config.ImagesConfig{}).Process(data, nil)
+				if err != nil {
+					t.Fatalf("Process: %v", err)
+				}
+				img := decode(t, result.Original)
+				width, height := img.Bounds().Dx(), img.Bounds().Dy()
+				if wantWidth := map[bool]int{false: 40, true: 20}[tt.wantRotated]; width != wantWidth || height != 60-wantWidth {
+					t.Fatalf("size = %dx%d, want width %d", width, height, wantWidth)
+				}
+				corners := [4]image.Point{
+					{width / 4, height / 4}, {width * 3 / 4, height / 4},
+					{width / 4, height * 3 / 4}, {width * 3 / 4, height * 3 / 4},
+				}
+				for i, corner := range corners {
+					if got := img.At(corner.X, corner.Y); !near(got, tt.want[i]) {
+						t.Errorf("corner %d = %v, want %v", i, got, tt.want[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestJPEGOrientationWithoutEXIF(t *testing.T) {
+	source := encodeJPEG(t, quadrants(8, 8))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "no exif", data: source},
+		{name: "orientation out of range", data: withEXIF(source, binary.BigEndian, 9, "")},
+		{name: "not a jpeg", data: encodePNG(t, quadrants(8, 8))},
+		{name: "truncated", data: source[:3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != 1 {
+				t.Fatalf("jpegOrientation = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestProcessStripsMetadata(t *testing.T) {
+	const secret = "GPS 55.7558 37.6173"
+	data := withEXIF(encodeJPEG(t, quadrants(64, 64)), binary.BigEndian, 6, secret)
+	result, err := testProcessor(config.ImagesConfig{}).Process(data, IconVariants)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	outputs := map[string]Encoded{"original": result.Original}
+	for name, variant := range result.Variants {
+		outputs[name] = variant
+	}
+	for name, encoded := range outputs {
+		if bytes.Contains(encoded.Data, []byte("Exif")) || bytes.Contains(encoded.Data, []byte(secret)) {
+			t.Errorf("%s keeps EXIF metadata", name)
+		}
+		if got := jpegOrientation(encoded.Data); got != 1 {
+			t.Errorf("%s orientation = %d, want 1", name, got)
+		}
+	}
+}
+
+func TestProcessVariants(t *testing.T) {
+	transparent := quadrants(300, 100)
+	transparent.SetNRGBA(0, 0, color.NRGBA{})
+
+	tests := []struct {
+		name         string
+		data         []byte
+		maxDimension int
+		variants     []Variant
+		wantOriginal image.Point
+		wantType     string
+		wantExt      string
+	}{
+		{name: "icon", data: encodePNG(t, quadrants(300, 100)), variants: IconVariants,
+			wantOriginal: image.Pt(300, 100), wantType: "image/jpeg", wantExt: ".jpg"},
+		{name: "image", data: encodeJPEG(t, quadrants(300, 100)), variants: ImageVariants,
+			wantOriginal: image.Pt(300, 100), wantType: "image/jpeg", wantExt: ".jpg"},
+		{name: "original is fitted to max dimension", data: encodePNG(t, quadrants(300, 100)), maxDimension: 120,
+			variants: IconVariants, wantOriginal: image.Pt(120, 40), wantType: "image/jpeg", wantExt: ".jpg"},
+		{name: "portrait original is fitted by height", data: encodePNG(t, quadrants(100, 300)), maxDimension: 120,
+			variants: IconVariants, wantOriginal: image.Pt(40, 120), wantType: "image/jpeg", wantExt: ".jpg"},
+		{name: "transparency is kept in png", data: encodePNG(t, transparent), variants: IconVariants,
+			wantOriginal: image.Pt(300, 100), wantType: "image/png", wantExt: ".png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := testProcessor(config.ImagesConfig{MaxDimension: tt.maxDimension}).Process(tt.data, tt.variants)
+			if err != nil {
+				t.Fatalf("Process: %v", err)
+			}
+			check := func(name string, encoded Encoded, want image.Point) {
+				if encoded.ContentType != tt.wantType || encoded.Ext != tt.wantExt {
+					t.Errorf("%s = %s %s, want %s %s", name, encoded.ContentType, encoded.Ext, tt.wantType, tt.wantExt)
+				}
+				if got := decode(t, encoded).Bounds().Size(); got != want {
+					t.Errorf("%s size = %v, want %v", name, got, want)
+				}
+			}
+			check("original", result.Original, tt.wantOriginal)
+			if len(result.Variants) != len(tt.variants) {
+				t.Fatalf("got %d variants, want %d", len(result.Variants), len(tt.variants))
+			}
+			for _, variant := range tt.variants {
+				encoded, ok := result.Variants[variant.Name]
+				if !ok {
+					t.Fatalf("variant %s is missing", variant.Name)
+				}
+				check(variant.Name, encoded, image.Pt(variant.Width, variant.Height))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"log/slog"
 )
 
@@ -10,3 +11,21 @@ func Err(err error) slog.Attr {
 		Value: slog.StringValue(err.Error()),
 	}
 }
+
+type loggerKey struct{}
+
+// WithLogger сохраняет логгер запроса в контексте
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext возвращает логгер запроса с request_id, user_id и маршрутом,
+// а если его нет (фоновые задачи) - fallback
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return fallback
+}
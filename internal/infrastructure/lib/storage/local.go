@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"challenge-service/config"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage хранит объекты в каталоге на диске. Используется для разработки и тестов,
+// файлы отдаются через Handler
+type LocalStorage struct {
+	cfg    config.LocalStorageConfig
+	secret []byte
+	log    *slog.Logger
+}
+
+func NewLocalStorage(cfg config.LocalStorageConfig, secret []byte, log *slog.Logger) (*LocalStorage, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("local storage requires secretKey for presigned urls")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir: %w", err)
+	}
+	if cfg.ServeUnsigned {
+		log.Warn("local storage serves files without signature, use only for development")
+	}
+	return &LocalStorage{cfg: cfg, secret: secret, log: log}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	filename, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return "", err
+	}
+	return s.publicURL(key), nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	filename, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.signature(cleanKey(key), expires))
+	return s.publicURL(key) + "?" + query.Encode(), nil
+}
+
+// Ping проверяет, что каталог хранилища существует и доступен на запись
+func (s *LocalStorage) Ping(ctx context.Context) error {
+	file, err := os.CreateTemp(s.cfg.Dir, ".ping-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+func (s *LocalStorage) KeyFromURL(url string) (string, bool) {
+	return keyFromURL(s.cfg.PublicURL, url)
+}
+
+// Handler раздает файлы хранилища: публичные объекты по URL из Put, остальные по ссылкам
+// из PresignGet. Подпись проверяется вместе со сроком действия ссылки. Без подписи закрытый файл
+// отдается, только если включен serveUnsigned
+func (s *LocalStorage) Handler(prefix string) http.Handler {
+	files := http.StripPrefix(prefix, http.FileServer(http.Dir(s.cfg.Dir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		key := cleanKey(strings.TrimPrefix(r.URL.Path, prefix))
+		signature := query.Get("signature")
+		if signature == "" && !s.cfg.ServeUnsigned && !s.public(key) {
+			http.Error(w, "signature is required", http.StatusForbidden)
+			return
+		}
+		if signature != "" {
+			expires := query.Get("expires")
+			expiresAt, err := strconv.ParseInt(expires, 10, 64)
+			if err != nil || time.Now().Unix() > expiresAt ||
+				!hmac.Equal([]byte(signature), []byte(s.signature(key, expires))) {
+				http.Error(w, "invalid or expired signature", http.StatusForbidden)
+				return
+			}
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+// public объект доступен без подписи: его ключ начинается с одного из publicPrefixes
+func (s *LocalStorage) public(key string) bool {
+	for _, prefix := range s.cfg.PublicPrefixes {
+		if prefix != "" && strings.HasPrefix(key, cleanKey(prefix)+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *LocalStorage) publicURL(key string) string {
+	return strings.TrimSuffix(s.cfg.PublicURL, "/") + "/" + uriEncode(cleanKey(key), false)
+}
+
+// path возвращает путь к файлу объекта, не выходящий за пределы каталога хранилища
+func (s *LocalStorage) path(key string) (string, error) {
+	key = cleanKey(key)
+	if key == "" || key == "." || strings.HasPrefix(key, "..") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.cfg.Dir, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) signature(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func cleanKey(key string) string {
+	return strings.TrimPrefix(path.Clean("/"+key), "/")
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"challenge-service/config"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageHandler(t *testing.T) {
+	const key = "icons/1.png"
+	const publicKey = "challenges/2026/01/2.png"
+	newStorage := func(t *testing.T, serveUnsigned bool) *LocalStorage {
+		t.Helper()
+		storage, err := NewLocalStorage(config.LocalStorageConfig{
+			Dir:            t.TempDir(),
+			PublicURL:      "http://localhost/media",
+			PublicPrefixes: []string{"challenges/"},
+			ServeUnsigned:  serveUnsigned,
+		}, []byte("secret"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+		if err != nil {
+			t.Fatalf("NewLocalStorage: %v", err)
+		}
+		for _, key := range []string{key, publicKey} {
+			if _, err := storage.Put(context.Background(), key, []byte("png"), "image/png"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+		}
+		return storage
+	}
+	presigned := func(t *testing.T, storage *LocalStorage, ttl time.Duration) string {
+		t.Helper()
+		link, err := storage.PresignGet(context.Background(), key, ttl)
+		if err != nil {
+			t.Fatalf("PresignGet: %v", err)
+		}
+		parsed, err := url.Parse(link)
+		if err != nil {
+			t.Fatalf("parse presigned url: %v", err)
+		}
+		return parsed.RequestURI()
+	}
+
+	tests := []struct {
+		name          string
+		serveUnsigned bool
+		target        func(t *testing.T, storage *LocalStorage) string
+		wantStatus    int
+	}{
+		{
+			name:       "unsigned request is rejected",
+			target:     func(*testing.T, *LocalStorage) string { return "/media/" + key },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "public object is served without signature",
+			target:     func(*testing.T, *LocalStorage) string { return "/media/" + publicKey },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "path traversal out of the public prefix is rejected",
+			target:     func(*testing.T, *LocalStorage) string { return "/media/challenges/../" + key },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:          "unsigned request is served in development mode",
+			serveUnsigned: true,
+			target:        func(*testing.T, *LocalStorage) string { return "/media/" + key },
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name: "presigned request is served",
+			target: func(t *testing.T, storage *LocalStorage) string {
+				return presigned(t, storage, time.Minute)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "expired link is rejected",
+			target: func(t *testing.T, storage *LocalStorage) string {
+				return presigned(t, storage, -time.Minute)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "signature for another key is rejected",
+			target: func(t *testing.T, storage *LocalStorage) string {
+				return strings.Replace(presigned(t, storage, time.Minute), "1.png", "2.png", 1)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "tampered signature is rejected even in development mode",
+			target: func(t *testing.T, storage *LocalStorage) string {
+				return strings.Replace(presigned(t, storage, time.Minute), "signature=", "signature=0", 1)
+			},
+			serveUnsigned: true,
+			wantStatus:    http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := newStorage(t, tt.serveUnsigned)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, tt.target(t, storage), nil)
+			storage.Handler("/media/").ServeHTTP(recorder, request)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// ProxyStorage загружает файлы через старый upload-сервис (POST {S3Url}/upload),
+// который возвращает URL файла в теле ответа. Удаление и временные ссылки не поддерживаются.
+type ProxyStorage struct {
+	url    string
+	log    *slog.Logger
+	client *http.Client
+}
+
+func NewProxyStorage(url string, log *slog.Logger) *ProxyStorage {
+	return &ProxyStorage{
+		url:    strings.TrimSuffix(url, "/"),
+		log:    log,
+		client: &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
+	}
+}
+
+func (s *ProxyStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", path.Base(key))
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/upload", body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("upload service returned %d", resp.StatusCode)
+	}
+	return strings.TrimSpace(string(respBody)), nil
+}
+
+func (s *ProxyStorage) Delete(ctx context.Context, key string) error {
+	return ErrNotSupported
+}
+
+func (s *ProxyStorage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
+
+// Ping проверяет, что upload-сервис отвечает. Любой ответ кроме 5xx считается успешным
+func (s *ProxyStorage) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.url+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("upload service returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *ProxyStorage) KeyFromURL(url string) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"bytes"
+	"challenge-service/config"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+	maxPresignTTL   = 7 * 24 * time.Hour
+)
+
+// S3Storage клиент S3-совместимого хранилища (AWS S3, MinIO, Yandex Object Storage) с подписью SigV4
+type S3Storage struct {
+	cfg      config.S3Config
+	log      *slog.Logger
+	client   *http.Client
+	endpoint *url.URL
+}
+
+func NewS3Storage(cfg config.S3Config, log *slog.Logger) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 storage requires bucket")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3 storage requires access and secret keys")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	return &S3Storage{
+		cfg:      cfg,
+		log:      log,
+		client:   &http.Client{Timeout: cfg.Timeout, Transport: tracing.Transport(nil)},
+		endpoint: endpoint,
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	objectURL := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, hashHex(data), time.Now().UTC())
+
+	if err := s.do(req); err != nil {
+		return "", fmt.Errorf("s3 put %s: %w", key, err)
+	}
+	return s.publicURL(key), nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, hashHex(nil), time.Now().UTC())
+	err = s.do(req)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("s3 delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Storage) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if ttl <= 0 || ttl > maxPresignTTL {
+		return "", fmt.Errorf("presign ttl must be between 1s and %s", maxPresignTTL)
+	}
+	return s.presign(key, ttl, time.Now().UTC()), nil
+}
+
+func (s *S3Storage) presign(key string, ttl time.Duration, now time.Time) string {
+	objectURL := s.objectURL(key)
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.cfg.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + objectURL.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(now, canonicalRequest))
+	objectURL.RawQuery = canonicalQuery(query)
+	return objectURL.String()
+}
+
+// Ping проверяет доступ к бакету запросом HEAD bucket
+func (s *S3Storage) Ping(ctx context.Context) error {
+	bucketURL := s.objectURL("")
+	bucketURL.Path = strings.TrimSuffix(bucketURL.Path, "/")
+	bucketURL.RawPath = strings.TrimSuffix(bucketURL.RawPath, "/")
+	if bucketURL.Path == "" {
+		bucketURL.Path, bucketURL.RawPath = "/", ""
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, bucketURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, hashHex(nil), time.Now().UTC())
+	if err := s.do(req); err != nil {
+		return fmt.Errorf("s3 head bucket %s: %w", s.cfg.Bucket, err)
+	}
+	return nil
+}
+
+func (s *S3Storage) KeyFromURL(url string) (string, bool) {
+	key, ok := keyFromURL(s.baseURL(), url)
+	if !ok {
+		return "", false
+	}
+	if prefix := s.prefix(); prefix != "" {
+		key, ok = strings.CutPrefix(key, prefix+"/")
+	}
+	return key, ok
+}
+
+func (s *S3Storage) prefix() string {
+	return strings.Trim(s.cfg.Prefix, "/")
+}
+
+func (s *S3Storage) objectKey(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	if prefix := s.prefix(); prefix != "" {
+		return prefix + "/" + key
+	}
+	return key
+}
+
+// objectURL адрес объекта в path-style (endpoint/bucket/key) или virtual-hosted (bucket.endpoint/key) виде
+func (s *S3Storage) objectURL(key string) *url.URL {
+	objectURL := *s.endpoint
+	escapedKey := uriEncode(s.objectKey(key), false)
+	if s.cfg.PathStyle {
+		objectURL.Path = s.endpoint.Path + "/" + s.cfg.Bucket + "/" + s.objectKey(key)
+		objectURL.RawPath = s.endpoint.Path + "/" + uriEncode(s.cfg.Bucket, true) + "/" + escapedKey
+	} else {
+		objectURL.Host = s.cfg.Bucket + "." + s.endpoint.Host
+		objectURL.Path = s.endpoint.Path + "/" + s.objectKey(key)
+		objectURL.RawPath = s.endpoint.Path + "/" + escapedKey
+	}
+	return &objectURL
+}
+
+func (s *S3Storage) baseURL() string {
+	if s.cfg.PublicURL != "" {
+		return strings.TrimSuffix(s.cfg.PublicURL, "/")
+	}
+	return strings.TrimSuffix(s.objectURL("").String(), "/")
+}
+
+func (s *S3Storage) publicURL(key string) string {
+	if s.cfg.PublicURL != "" {
+		return s.baseURL() + "/" + uriEncode(s.objectKey(key), false)
+	}
+	return s.objectURL(key).String()
+}
+
+func (s *S3Storage) do(req *http.Request) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// sign добавляет к запросу заголовок Authorization по схеме AWS Signature Version 4
+func (s *S3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.cfg.AccessKey, s.scope(now), signedHeaders, s.signature(now, canonicalRequest)))
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func (s *S3Storage) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(amzDateFormat),
+		s.scope(now),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode кодирует строку по правилам SigV4: не кодируются только A-Z a-z 0-9 - _ . ~ (и '/', если encodeSlash=false)
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
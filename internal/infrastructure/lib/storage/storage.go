@@ -0,0 +1,76 @@
+// Package storage хранение изображений вызовов: S3-совместимое хранилище,
+// локальная файловая система для разработки и тестов и старый upload-прокси.
+package storage
+
+import (
+	"challenge-service/config"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// NamespaceChallenges каталог публичных изображений вызовов
+const NamespaceChallenges = "challenges"
+
+const (
+	BackendS3    = "s3"
+	BackendLocal = "local"
+	BackendProxy = "proxy"
+)
+
+var (
+	ErrNotSupported = errors.New("operation is not supported by storage backend")
+	ErrNotFound     = errors.New("object not found")
+)
+
+type Storage interface {
+	// Put сохраняет объект и возвращает его публичный URL
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+	// PresignGet возвращает временную ссылку на чтение объекта
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// KeyFromURL восстанавливает ключ объекта по публичному URL, выданному Put
+	KeyFromURL(url string) (string, bool)
+	// Ping проверяет доступность хранилища
+	Ping(ctx context.Context) error
+}
+
+func NewStorage(cfg *config.Config, log *slog.Logger) (Storage, error) {
+	switch cfg.Storage.Backend {
+	case BackendS3:
+		return NewS3Storage(cfg.Storage.S3, log)
+	case BackendLocal:
+		return NewLocalStorage(cfg.Storage.Local, []byte(cfg.SecretKey), log)
+	case BackendProxy, "":
+		return NewProxyStorage(cfg.S3Url, log), nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+}
+
+// NewObjectKey генерирует уникальный ключ объекта с сохранением расширения исходного файла
+func NewObjectKey(namespace string, filename string) string {
+	ext := strings.ToLower(path.Ext(filename))
+	return path.Join(namespace, time.Now().UTC().Format("2006/01"), uuid.NewString()+ext)
+}
+
+func keyFromURL(baseURL string, rawURL string) (string, bool) {
+	prefix := strings.TrimSuffix(baseURL, "/") + "/"
+	if baseURL == "" || !strings.HasPrefix(rawURL, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(rawURL, prefix)
+	if i := strings.IndexByte(key, '?'); i >= 0 {
+		key = key[:i]
+	}
+	key, err := url.PathUnescape(key)
+	if err != nil {
+		return "", false
+	}
+	return key, key != ""
+}
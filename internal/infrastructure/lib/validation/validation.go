@@ -0,0 +1,170 @@
+// Package validation декларативная проверка команд и запросов по тегам validate
+// с ошибками по каждому полю в машиночитаемом виде.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Коды ошибок полей, на которые опирается фронтенд
+const (
+	CodeRequired      = "required"
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeTooSmall      = "too_small"
+	CodeTooLarge      = "too_large"
+	CodeInvalidChoice = "invalid_choice"
+	CodeMustBeAfter   = "must_be_after"
+	CodeDuplicate     = "duplicate"
+	CodeInvalid       = "invalid"
+)
+
+// FieldError ошибка одного поля. Field - имя поля в JSON (вложенные через точку), Param - граница
+// или допустимые значения правила
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors ошибки всех невалидных полей
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, field := range e {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewError ошибка одного поля для проверок, которые не выражаются тегами
+func NewError(field string, code string, param string, message string) Errors {
+	return Errors{{Field: field, Code: code, Param: param, Message: message}}
+}
+
+type Validator struct {
+	validate *validator.Validate
+}
+
+func New() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(jsonName)
+	return &Validator{validate: validate}
+}
+
+// Struct проверяет значение по тегам validate и возвращает Errors со всеми невалидными полями
+func (v *Validator) Struct(value any) error {
+	err := v.validate.Struct(value)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		var invalid *validator.InvalidValidationError
+		if errors.As(err, &invalid) {
+			// не структура, например nil указатель: проверять нечего
+			return nil
+		}
+		return err
+	}
+	result := make(Errors, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		result = append(result, toFieldError(fieldError))
+	}
+	return result
+}
+
+func toFieldError(fieldError validator.FieldError) FieldError {
+	field := fieldError.Namespace()
+	// первый сегмент - имя типа команды или запроса
+	if i := strings.IndexByte(field, '.'); i >= 0 {
+		field = field[i+1:]
+	}
+	param := fieldError.Param()
+	sized := isSized(fieldError.Kind())
+	unit := "items"
+	if fieldError.Kind() == reflect.String {
+		unit = "characters"
+	}
+	var code, message string
+	switch fieldError.Tag() {
+	case "required":
+		code, message = CodeRequired, "is required"
+	case "min", "gte", "gt":
+		if sized {
+			code, message = CodeTooShort, fmt.Sprintf("must contain %s %s %s", comparison(fieldError.Tag()), param, unit)
+		} else {
+			code, message = CodeTooSmall, fmt.Sprintf("must be %s %s", comparison(fieldError.Tag()), param)
+		}
+	case "max", "lte", "lt":
+		if sized {
+			code, message = CodeTooLong, fmt.Sprintf("must contain %s %s %s", comparison(fieldError.Tag()), param, unit)
+		} else {
+			code, message = CodeTooLarge, fmt.Sprintf("must be %s %s", comparison(fieldError.Tag()), param)
+		}
+	case "oneof":
+		code, message = CodeInvalidChoice, "must be one of: "+strings.Join(strings.Fields(param), ", ")
+	case "gtfield", "gtefield":
+		param = snakeCase(param)
+		if fieldError.Type() == reflect.TypeFor[time.Time]() {
+			code, message = CodeMustBeAfter, "must be after "+param
+		} else {
+			code, message = CodeTooSmall, "must be greater than "+param
+		}
+	case "unique":
+		code, message = CodeDuplicate, "must not contain duplicates"
+	default:
+		code, message = CodeInvalid, "failed the "+fieldError.Tag()+" rule"
+	}
+	return FieldError{Field: field, Code: code, Param: param, Message: message}
+}
+
+func comparison(tag string) string {
+	switch tag {
+	case "gt":
+		return "more than"
+	case "lt":
+		return "less than"
+	case "min", "gte":
+		return "at least"
+	}
+	return "at most"
+}
+
+func isSized(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// jsonName имя поля из тега json, без него - имя поля в snake_case
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return snakeCase(field.Name)
+	}
+	return name
+}
+
+func snakeCase(name string) string {
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// граница слова: StartDate -> start_date, CreatorID -> creator_id
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testCommand struct {
+	Name      string      `json:"name" validate:"required,max=5"`
+	Value     float64     `json:"value" validate:"gt=0,lte=100"`
+	Kind      string      `json:"kind,omitempty" validate:"omitempty,oneof=family personal"`
+	Tags      []string    `json:"tags" validate:"max=2,unique"`
+	StartDate time.Time   `json:"start_date"`
+	EndDate   time.Time   `json:"end_date" validate:"gtfield=StartDate"`
+	CreatorID int64       `validate:"gte=1"`
+	Address   testAddress `json:"address"`
+	Code      string      `json:"code" validate:"omitempty,alpha"`
+	Ignored   string      `json:"-" validate:"max=1"`
+}
+
+func validCommand() testCommand {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return testCommand{
+		Name:      "run",
+		Value:     1,
+		StartDate: start,
+		EndDate:   start.Add(time.Hour),
+		CreatorID: 1,
+		Address:   testAddress{City: "Kazan"},
+	}
+}
+
+func TestValidatorStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *testCommand)
+		want   Errors
+	}{
+		{name: "valid", modify: func(*testCommand) {}},
+		{name: "required", modify: func(c *testCommand) { c.Name = "" },
+			want: Errors{{Field: "name", Code: CodeRequired, Message: "is required"}}},
+		{name: "string too long", modify: func(c *testCommand) { c.Name = "running" },
+			want: Errors{{Field: "name", Code: CodeTooLong, Param: "5", Message: "must contain at most 5 characters"}}},
+		{name: "number too small", modify: func(c *testCommand) { c.Value = 0 },
+			want: Errors{{Field: "value", Code: CodeTooSmall, Param: "0", Message: "must be more than 0"}}},
+		{name: "number too large", modify: func(c *testCommand) { c.Value = 101 },
+			want: Errors{{Field: "value", Code: CodeTooLarge, Param: "100", Message: "must be at most 100"}}},
+		{name: "invalid choice", modify: func(c *testCommand) { c.Kind = "group" },
+			want: Errors{{Field: "kind", Code: CodeInvalidChoice, Param: "family personal",
+				Message: "must be one of: family, personal"}}},
+		{name: "too many items", modify: func(c *testCommand) { c.Tags = []string{"a", "b", "c"} },
+			want: Errors{{Field: "tags", Code: CodeTooLong, Param: "2", Message: "must contain at most 2 items"}}},
+		{name: "duplicates", modify: func(c *testCommand) { c.Tags = []string{"a", "a"} },
+			want: Errors{{Field: "tags", Code: CodeDuplicate, Message: "must not contain duplicates"}}},
+		{name: "date order", modify: func(c *testCommand) { c.EndDate = c.StartDate },
+			want: Errors{{Field: "end_date", Code: CodeMustBeAfter, Param: "start_date",
+				Message: "must be after start_date"}}},
+		{name: "field without json tag is snake cased", modify: func(c *testCommand) { c.CreatorID = 0 },
+			want: Errors{{Field: "creator_id", Code: CodeTooSmall, Param: "1", Message: "must be at least 1"}}},
+		{name: "nested field", modify: func(c *testCommand) { c.Address.City = "" },
+			want: Errors{{Field: "address.city", Code: CodeRequired, Message: "is required"}}},
+		{name: "unknown rule", modify: func(c *testCommand) { c.Code = "a1" },
+			want: Errors{{Field: "code", Code: CodeInvalid, Message: "failed the alpha rule"}}},
+		{name: "all invalid fields are reported", modify: func(c *testCommand) { c.Name = ""; c.Value = 0 },
+			want: Errors{
+				{Field: "name", Code: CodeRequired, Message: "is required"},
+				{Field: "value", Code: CodeTooSmall, Param: "0", Message: "must be more than 0"},
+			}},
+	}
+	validator := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := validCommand()
+			tt.modify(&command)
+			err := validator.Struct(&command)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var got Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("err = %v, want Errors", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("field %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorStructNotAStruct(t *testing.T) {
+	var command *testCommand
+	if err := New().Struct(command); err != nil {
+		t.Fatalf("nil pointer: unexpected error %v", err)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":       "name",
+		"StartDate":  "start_date",
+		"CreatorID":  "creator_id",
+		"HTTPStatus": "http_status",
+	}
+	for name, want := range tests {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package lifecycle запуск и упорядоченная остановка компонентов сервиса.
+package lifecycle
+
+import (
+	"challenge-service/internal/infrastructure/lib/log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Component часть сервиса с управляемым жизненным циклом. Start не должен блокироваться,
+// Stop должен завершить работу компонента до отмены ctx
+type Component interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Failer компонент, который может аварийно завершиться после запуска, например HTTP сервер
+type Failer interface {
+	Failed() <-chan error
+}
+
+// Hook компонент из пары функций, любая из них может быть nil
+type Hook struct {
+	ComponentName string
+	OnStart       func(ctx context.Context) error
+	OnStop        func(ctx context.Context) error
+}
+
+func (h Hook) Name() string {
+	return h.ComponentName
+}
+
+func (h Hook) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+	return h.OnStart(ctx)
+}
+
+func (h Hook) Stop(ctx context.Context) error {
+	if h.OnStop == nil {
+		return nil
+	}
+	return h.OnStop(ctx)
+}
+
+// Manager запускает компоненты в порядке добавления и останавливает в обратном порядке
+// по сигналу SIGINT/SIGTERM или при аварии одного из компонентов
+type Manager struct {
+	log         *slog.Logger
+	stopTimeout time.Duration
+	components  []Component
+}
+
+// NewManager создает менеджер. stopTimeout ограничивает остановку каждого компонента
+func NewManager(log *slog.Logger, stopTimeout time.Duration) *Manager {
+	return &Manager{log: log, stopTimeout: stopTimeout}
+}
+
+func (m *Manager) Add(components ...Component) {
+	m.components = append(m.components, components...)
+}
+
+// Run запускает компоненты и блокируется до сигнала, отмены ctx или аварии компонента,
+// после чего останавливает запущенные компоненты. Возвращает ошибку запуска или аварии
+func (m *Manager) Run(ctx context.Context) error {
+	signalCtx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	// компоненты живут до своей остановки, а не до сигнала
+	componentCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	failed := make(chan error, len(m.components))
+	started := 0
+	var runErr error
+	for _, component := range m.components {
+		if err := component.Start(componentCtx); err != nil {
+			runErr = fmt.Errorf("start %s: %w", component.Name(), err)
+			break
+		}
+		m.log.Info("component started", slog.String("component", component.Name()))
+		started++
+		if failer, ok := component.(Failer); ok {
+			go func(name string, errs <-chan error) {
+				if err, ok := <-errs; ok && err != nil {
+					failed <- fmt.Errorf("%s: %w", name, err)
+				}
+			}(component.Name(), failer.Failed())
+		}
+	}
+
+	if runErr == nil {
+		select {
+		case <-signalCtx.Done():
+			m.log.Info("shutting down")
+		case runErr = <-failed:
+			m.log.Error("component failed, shutting down", log.Err(runErr))
+		}
+	}
+
+	for i := started - 1; i >= 0; i-- {
+		if err := m.stop(m.components[i]); err != nil {
+			m.log.Error("failed to stop component", slog.String("component", m.components[i].Name()), log.Err(err))
+		}
+	}
+	return runErr
+}
+
+// stop останавливает компонент, не дожидаясь его дольше stopTimeout
+func (m *Manager) stop(component Component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.stopTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- component.Stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			m.log.Info("component stopped", slog.String("component", component.Name()))
+		}
+		return err
+	case <-ctx.Done():
+		return errors.New("stop timed out")
+	}
+}
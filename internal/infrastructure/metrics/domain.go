@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// challengeCollector доменные показатели, запрашиваемые из БД при каждом сборе метрик
+type challengeCollector struct {
+	db      *sql.DB
+	timeout time.Duration
+
+	challenges   *prometheus.Desc
+	participants *prometheus.Desc
+	scrapeErrors prometheus.Counter
+}
+
+func newChallengeCollector(db *sql.DB, timeout time.Duration) *challengeCollector {
+	return &challengeCollector{
+		db:      db,
+		timeout: timeout,
+		challenges: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "challenges"),
+			"Challenges by lifecycle status.", []string{"status"}, nil),
+		participants: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_participants"),
+			"Participants of active challenges.", nil, nil),
+		scrapeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "domain_scrape_errors_total",
+			Help:      "Failed domain metric queries.",
+		}),
+	}
+}
+
+func (c *challengeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.challenges
+	ch <- c.participants
+	c.scrapeErrors.Describe(ch)
+}
+
+func (c *challengeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.collectChallenges(ctx, ch); err != nil {
+		c.scrapeErrors.Inc()
+	}
+	var participants int64
+	err := c.db.QueryRowContext(ctx, `SELECT count(*) FROM authentication_participant p
+		JOIN authentication_challenge c ON c.id = p.challenge_id WHERE c.status = 'active' AND c.deleted_at IS NULL`).Scan(&participants)
+	if err != nil {
+		c.scrapeErrors.Inc()
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.participants, prometheus.GaugeValue, float64(participants))
+	}
+	c.scrapeErrors.Collect(ch)
+}
+
+func (c *challengeCollector) collectChallenges(ctx context.Context, ch chan<- prometheus.Metric) error {
+	rows, err := c.db.QueryContext(ctx, "SELECT status, count(*) FROM authentication_challenge WHERE deleted_at IS NULL GROUP BY status")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(c.challenges, prometheus.GaugeValue, float64(count), status)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,126 @@
+// Package metrics метрики Prometheus: HTTP запросы, команды и запросы CQRS, методы репозитория,
+// пул соединений БД и доменные показатели.
+package metrics
+
+import (
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "challenge_service"
+
+const (
+	outcomeOK    = "ok"
+	outcomeError = "error"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration    *prometheus.HistogramVec
+	dispatchTotal   *prometheus.CounterVec
+	dispatchSeconds *prometheus.HistogramVec
+	repoSeconds     *prometheus.HistogramVec
+	registrations   *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		dispatchTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cqrs_dispatch_total",
+			Help:      "Dispatched commands and queries by name and outcome.",
+		}, []string{"kind", "name", "outcome"}),
+		dispatchSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cqrs_dispatch_duration_seconds",
+			Help:      "Command and query handler latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"kind", "name"}),
+		repoSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_duration_seconds",
+			Help:      "Repository method latency by method and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"method", "outcome"}),
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "participant_registrations_total",
+			Help:      "Participant registrations by kind (user or team), rate() gives registrations per hour.",
+		}, []string{"kind"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration, m.dispatchTotal, m.dispatchSeconds, m.repoSeconds, m.registrations,
+	)
+	return m
+}
+
+// RegisterDB добавляет метрики пула соединений и доменные показатели, которые считываются из БД при сборе
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string, timeout time.Duration) {
+	m.registry.MustRegister(
+		collectors.NewDBStatsCollector(db, dbName),
+		newChallengeCollector(db, timeout),
+	)
+}
+
+// SubscribeEvents считает доменные события шины
+func (m *Metrics) SubscribeEvents(bus *cqrs.EventBus) {
+	cqrs.SubscribeFunc(bus, func(ctx context.Context, event *events.ParticipantRegistered) error {
+		kind := "user"
+		if event.Participant.TeamID != 0 {
+			kind = "team"
+		}
+		m.registrations.WithLabelValues(kind).Inc()
+		return nil
+	})
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTP route - шаблон маршрута (/challenges/:id), а не фактический путь
+func (m *Metrics) ObserveHTTP(route string, method string, status int, duration time.Duration) {
+	m.httpDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveCommand(name string, duration time.Duration, err error) {
+	m.observeDispatch("command", name, duration, err)
+}
+
+func (m *Metrics) ObserveQuery(name string, duration time.Duration, err error) {
+	m.observeDispatch("query", name, duration, err)
+}
+
+func (m *Metrics) ObserveRepository(method string, duration time.Duration, err error) {
+	m.repoSeconds.WithLabelValues(method, outcome(err)).Observe(duration.Seconds())
+}
+
+func (m *Metrics) observeDispatch(kind string, name string, duration time.Duration, err error) {
+	m.dispatchTotal.WithLabelValues(kind, name, outcome(err)).Inc()
+	m.dispatchSeconds.WithLabelValues(kind, name).Observe(duration.Seconds())
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return outcomeError
+	}
+	return outcomeOK
+}
@@ -0,0 +1,256 @@
+package notifications
+
+import (
+	"bytes"
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// defaultTemplates шаблоны сообщений по умолчанию, переопределяются в notifications.templates
+var defaultTemplates = map[notifier_interface.Event]string{
+	notifier_interface.EventChallengeCreated:      `Вызов «{{.ChallengeName}}» создан. Не забудьте его опубликовать!`,
+	notifier_interface.EventRegistrationConfirmed: `Вы зарегистрированы на вызов «{{.ChallengeName}}». Старт {{.StartDate}}.`,
+	notifier_interface.EventDaysLeft:              `До конца вызова «{{.ChallengeName}}» осталось дней: {{.DaysLeft}}.`,
+	notifier_interface.EventChallengeClosed:       `Вызов «{{.ChallengeName}}» завершен.`,
+	notifier_interface.EventParticipantFinished:   `Поздравляем! Вы прошли вызов «{{.ChallengeName}}».`,
+}
+
+type message struct {
+	Event    notifier_interface.Event `json:"event"`
+	UserID   int64                    `json:"user_id,omitempty"`
+	TeamID   int64                    `json:"team_id,omitempty"`
+	Message  string                   `json:"message"`
+	attempts int
+	// spanContext спан, в котором было создано уведомление, отправка продолжает его трассу
+	spanContext trace.SpanContext
+}
+
+// Dispatcher асинхронно рассылает уведомления через сервис сообщений (TgMessageURL)
+// с повторными попытками при сетевых ошибках и ответах 429/5xx
+type Dispatcher struct {
+	cfg       config.NotificationsConfig
+	url       string
+	log       *slog.Logger
+	client    *http.Client
+	templates map[notifier_interface.Event]*template.Template
+
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan message
+	done    chan struct{} // закрывается в Stop и прерывает ожидание перед повтором
+	wg      sync.WaitGroup
+}
+
+// NewDispatcher создает рассыльщик. client можно подменить, например, на клиент httptest сервера
+func NewDispatcher(cfg *config.Config, log *slog.Logger, client *http.Client) (*Dispatcher, error) {
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Notifications.Timeout, Transport: tracing.Transport(nil)}
+	}
+	dispatcher := &Dispatcher{
+		cfg:       cfg.Notifications,
+		url:       cfg.TgMessageURL,
+		log:       log,
+		client:    client,
+		templates: make(map[notifier_interface.Event]*template.Template),
+		queue:     make(chan message, cfg.Notifications.QueueSize),
+		done:      make(chan struct{}),
+	}
+	for event, text := range defaultTemplates {
+		if override, ok := cfg.Notifications.Templates[string(event)]; ok {
+			text = override
+		}
+		tmpl, err := template.New(string(event)).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for %s: %w", event, err)
+		}
+		dispatcher.templates[event] = tmpl
+	}
+	return dispatcher, nil
+}
+
+// Start запускает воркеры отправки
+func (d *Dispatcher) Start() {
+	for i := 0; i < max(d.cfg.Workers, 1); i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for msg := range d.queue {
+				d.deliver(msg)
+			}
+		}()
+	}
+}
+
+// Stop перестает принимать уведомления и дожидается отправки очереди или отмены ctx.
+// Оставшиеся в очереди уведомления отправляются по одному разу, без повторов
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.done)
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) Notify(ctx context.Context, notification notifier_interface.Notification) {
+	if d.url == "" {
+		return
+	}
+	tmpl, ok := d.templates[notification.Event]
+	if !ok {
+		d.log.Warn("no template for notification event", slog.String("event", string(notification.Event)))
+		return
+	}
+	var text strings.Builder
+	if err := tmpl.Execute(&text, notification.Data); err != nil {
+		d.log.Error("failed to render notification", slog.String("event", string(notification.Event)), log.Err(err))
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.stopped {
+		d.log.Warn("notification dropped: dispatcher stopped", slog.String("event", string(notification.Event)))
+		return
+	}
+	for _, recipient := range notification.Recipients {
+		msg := message{
+			Event:   notification.Event,
+			UserID:  recipient.UserID,
+			TeamID:  recipient.TeamID,
+			Message: text.String(),
+
+			spanContext: trace.SpanContextFromContext(ctx),
+		}
+		select {
+		case d.queue <- msg:
+		default:
+			d.log.Warn("notification dropped: queue is full", slog.String("event", string(notification.Event)))
+		}
+	}
+}
+
+// Ping проверяет, что сервис сообщений отвечает. Любой ответ кроме 5xx считается успешным,
+// при выключенных уведомлениях проверка всегда проходит
+func (d *Dispatcher) Ping(ctx context.Context) error {
+	if d.url == "" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, d.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("messaging service returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Dispatcher) deliver(msg message) {
+	backoff := d.cfg.RetryBackoff
+	for {
+		msg.attempts++
+		err := d.send(msg)
+		if err == nil {
+			return
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || msg.attempts > d.cfg.MaxRetries {
+			d.log.Error("failed to send notification", slog.String("event", string(msg.Event)),
+				slog.Int64("user_id", msg.UserID), slog.Int64("team_id", msg.TeamID),
+				slog.Int("attempts", msg.attempts), log.Err(err))
+			return
+		}
+		d.log.Warn("notification send failed, retrying", slog.String("event", string(msg.Event)),
+			slog.Int("attempt", msg.attempts), log.Err(err))
+		if !d.wait(backoff) {
+			d.log.Error("notification dropped: dispatcher stopped before retry", slog.String("event", string(msg.Event)),
+				slog.Int64("user_id", msg.UserID), slog.Int64("team_id", msg.TeamID),
+				slog.Int("attempts", msg.attempts), log.Err(err))
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// wait ждет перед повтором, false - рассыльщик остановлен раньше
+func (d *Dispatcher) wait(backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-d.done:
+		return false
+	}
+}
+
+type permanentError struct {
+	status int
+}
+
+func (e *permanentError) Error() string {
+	return fmt.Sprintf("messaging service rejected notification with status %d", e.status)
+}
+
+func (d *Dispatcher) send(msg message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), msg.spanContext)
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("messaging service returned %d", resp.StatusCode)
+	}
+	return &permanentError{status: resp.StatusCode}
+}
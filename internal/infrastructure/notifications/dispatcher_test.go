@@ -0,0 +1,152 @@
+package notifications
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// messagingStandIn локальный сервис сообщений: отвечает статусами из statuses по очереди,
+// последний статус повторяется, полученные сообщения отправляет в requests
+type messagingStandIn struct {
+	mu       sync.Mutex
+	statuses []int
+	calls    int
+	requests chan message
+}
+
+func newMessagingStandIn(t *testing.T, statuses ...int) (*messagingStandIn, *httptest.Server) {
+	standIn := &messagingStandIn{statuses: statuses, requests: make(chan message, 16)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		standIn.mu.Lock()
+		status := standIn.statuses[min(standIn.calls, len(standIn.statuses)-1)]
+		standIn.calls++
+		standIn.mu.Unlock()
+		standIn.requests <- msg
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func newTestDispatcher(t *testing.T, url string, maxRetries int, backoff time.Duration) *Dispatcher {
+	cfg := &config.Config{
+		TgMessageURL: url,
+		Notifications: config.NotificationsConfig{
+			Workers:      1,
+			QueueSize:    8,
+			MaxRetries:   maxRetries,
+			RetryBackoff: backoff,
+			Timeout:      time.Second,
+		},
+	}
+	dispatcher, err := NewDispatcher(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	if err != nil {
+		t.Fatalf("NewDispatcher: %v", err)
+	}
+	dispatcher.Start()
+	return dispatcher
+}
+
+func notifyClosed(dispatcher *Dispatcher) {
+	dispatcher.Notify(context.Background(), notifier_interface.Notification{
+		Event:      notifier_interface.EventChallengeClosed,
+		Recipients: []notifier_interface.Recipient{{UserID: 42}},
+		Data:       map[string]any{"ChallengeName": "Зарядка"},
+	})
+}
+
+func TestDispatcherDelivery(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		wantAttempts int
+	}{
+		{name: "success", statuses: []int{http.StatusOK}, maxRetries: 3, wantAttempts: 1},
+		{name: "retry then succeed", statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			maxRetries: 3, wantAttempts: 3},
+		{name: "give up after retries", statuses: []int{http.StatusInternalServerError}, maxRetries: 2, wantAttempts: 3},
+		{name: "permanent error is not retried", statuses: []int{http.StatusBadRequest}, maxRetries: 3, wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn, server := newMessagingStandIn(t, tt.statuses...)
+			dispatcher := newTestDispatcher(t, server.URL, tt.maxRetries, time.Millisecond)
+
+			notifyClosed(dispatcher)
+			for attempt := 1; attempt <= tt.wantAttempts; attempt++ {
+				select {
+				case msg := <-standIn.requests:
+					if msg.Event != notifier_interface.EventChallengeClosed || msg.UserID != 42 {
+						t.Fatalf("attempt %d: unexpected message %+v", attempt, msg)
+					}
+					if msg.Message != "Вызов «Зарядка» завершен." {
+						t.Fatalf("attempt %d: unexpected text %q", attempt, msg.Message)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatalf("got %d attempts, want %d", attempt-1, tt.wantAttempts)
+				}
+			}
+			select {
+			case <-standIn.requests:
+				t.Fatalf("got more than %d attempts", tt.wantAttempts)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			if err := dispatcher.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop: %v", err)
+			}
+		})
+	}
+}
+
+func TestDispatcherStopInterruptsBackoff(t *testing.T) {
+	standIn, server := newMessagingStandIn(t, http.StatusServiceUnavailable)
+	dispatcher := newTestDispatcher(t, server.URL, 3, time.Hour)
+
+	notifyClosed(dispatcher)
+	select {
+	case <-standIn.requests:
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification was not sent")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := dispatcher.Stop(ctx); err != nil {
+		t.Fatalf("Stop waited for the retry backoff: %v", err)
+	}
+	select {
+	case <-standIn.requests:
+		t.Fatal("notification was retried after Stop")
+	default:
+	}
+}
+
+func TestDispatcherDropsAfterStop(t *testing.T) {
+	standIn, server := newMessagingStandIn(t, http.StatusOK)
+	dispatcher := newTestDispatcher(t, server.URL, 3, time.Millisecond)
+	if err := dispatcher.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	notifyClosed(dispatcher)
+	select {
+	case <-standIn.requests:
+		t.Fatal("notification was sent after Stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"challenge-service/internal/infrastructure/outbox"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const SinkNotifications = "notifications"
+
+// OutboxSink приемник outbox, рассылающий уведомления по доменным событиям. Уведомления уходят только
+// после коммита изменений и переживают перезапуск сервиса: событие остается в outbox до доставки
+type OutboxSink struct {
+	notifier notifier_interface.NotifierInterface
+}
+
+func NewOutboxSink(notifier notifier_interface.NotifierInterface) *OutboxSink {
+	return &OutboxSink{notifier: notifier}
+}
+
+func (s *OutboxSink) Name() string {
+	return SinkNotifications
+}
+
+// Deliver ставит уведомления события в очередь рассылки. События, по которым уведомления
+// не рассылаются, и события, не известные этой версии сервиса, пропускаются
+func (s *OutboxSink) Deliver(ctx context.Context, envelope outbox.Envelope) error {
+	event, err := events.Decode(envelope.Event, envelope.Payload)
+	if errors.Is(err, events.ErrUnknownEvent) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", envelope.Event, err)
+	}
+	for _, notification := range events.Notifications(event) {
+		s.notifier.Notify(ctx, notification)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/outbox"
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// recordingNotifier запоминает уведомления вместо отправки
+type recordingNotifier struct {
+	notifications []notifier_interface.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notifier_interface.Notification) {
+	n.notifications = append(n.notifications, notification)
+}
+
+func TestOutboxSink(t *testing.T) {
+	challenge := &entity.AuthenticationChallenge{ID: 7, Name: "Зарядка", CreatorID: 1}
+	envelope := func(t *testing.T, event cqrs.Event) outbox.Envelope {
+		t.Helper()
+		message, err := outbox.NewMessage(event)
+		if err != nil {
+			t.Fatalf("NewMessage: %v", err)
+		}
+		return message.Envelope()
+	}
+
+	tests := []struct {
+		name       string
+		envelope   func(t *testing.T) outbox.Envelope
+		wantEvents []notifier_interface.Event
+		wantErr    bool
+	}{
+		{
+			name: "registration",
+			envelope: func(t *testing.T) outbox.Envelope {
+				return envelope(t, events.NewParticipantRegistered(challenge, &entity.AuthenticationParticipant{UserID: 42}))
+			},
+			wantEvents: []notifier_interface.Event{notifier_interface.EventRegistrationConfirmed},
+		},
+		{
+			name: "closed challenge",
+			envelope: func(t *testing.T) outbox.Envelope {
+				return envelope(t, events.NewChallengeClosed(challenge, []*entity.AuthenticationParticipant{
+					{UserID: 42, Status: entity.ParticipantStatusCompleted},
+					{UserID: 43, Status: entity.ParticipantStatusFailed},
+				}))
+			},
+			wantEvents: []notifier_interface.Event{notifier_interface.EventChallengeClosed,
+				notifier_interface.EventParticipantFinished},
+		},
+		{
+			name:     "event without notifications",
+			envelope: func(t *testing.T) outbox.Envelope { return envelope(t, events.NewChallengeDeleted(7)) },
+		},
+		{
+			name: "unknown event",
+			envelope: func(*testing.T) outbox.Envelope {
+				return outbox.Envelope{Event: "challenge.archived", Payload: json.RawMessage(`{}`)}
+			},
+		},
+		{
+			name: "malformed payload",
+			envelope: func(*testing.T) outbox.Envelope {
+				return outbox.Envelope{Event: events.ChallengeClosedEvent, Payload: json.RawMessage(`[]`)}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &recordingNotifier{}
+			err := NewOutboxSink(notifier).Deliver(context.Background(), tt.envelope(t))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Deliver err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []notifier_interface.Event
+			for _, notification := range notifier.notifications {
+				got = append(got, notification.Event)
+				if notification.Data["ChallengeName"] != challenge.Name {
+					t.Errorf("%s data = %v", notification.Event, notification.Data)
+				}
+			}
+			if !slices.Equal(got, tt.wantEvents) {
+				t.Fatalf("notified %q, want %q", got, tt.wantEvents)
+			}
+		})
+	}
+	t.Run("finished participants", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		closed := events.NewChallengeClosed(challenge, []*entity.AuthenticationParticipant{
+			{UserID: 42, Status: entity.ParticipantStatusCompleted},
+			{UserID: 43, Status: entity.ParticipantStatusFailed},
+		})
+		if err := NewOutboxSink(notifier).Deliver(context.Background(), envelope(t, closed)); err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+		finished := notifier.notifications[1].Recipients
+		if len(finished) != 1 || finished[0].UserID != 42 {
+			t.Fatalf("finished recipients = %+v, want user 42", finished)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+// Package outbox транзакционный outbox: события сохраняются в одной транзакции с изменениями
+// вызовов и доставляются во внешние приемники отдельным воркером с гарантией at-least-once.
+package outbox
+
+import (
+	"challenge-service/internal/infrastructure/cqrs"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// Message событие, ожидающее доставки. Сообщения одного агрегата доставляются строго по порядку ID
+type Message struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement:true" json:"-"`
+	EventID        string     `gorm:"type:uuid;not null" json:"id"`
+	AggregateID    int64      `gorm:"not null" json:"aggregate_id"`
+	EventName      string     `gorm:"type:varchar(128);not null" json:"event"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"-"`
+	OccurredAt     time.Time  `gorm:"type:timestamptz;not null" json:"occurred_at"`
+	CreatedAt      time.Time  `gorm:"type:timestamptz;not null" json:"-"`
+	Attempts       int        `gorm:"not null;default:0" json:"-"`
+	NextAttemptAt  time.Time  `gorm:"type:timestamptz;not null" json:"-"`
+	LastError      string     `gorm:"type:text;not null;default:''" json:"-"`
+	DeliveredAt    *time.Time `gorm:"type:timestamptz" json:"-"`
+	DeadLetteredAt *time.Time `gorm:"type:timestamptz" json:"-"`
+	// DeliveredSinks приемники, уже принявшие сообщение, при повторе они пропускаются
+	DeliveredSinks SinkNames `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+func NewMessage(event cqrs.Event) (*Message, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	return &Message{
+		EventID:       uuid.NewString(),
+		AggregateID:   event.GetAggregateID(),
+		EventName:     event.EventName(),
+		Payload:       string(payload),
+		OccurredAt:    event.GetOccurredAt(),
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// Envelope сообщение в том виде, в котором оно передается приемникам
+type Envelope struct {
+	ID          string          `json:"id"`
+	Event       string          `json:"event"`
+	AggregateID int64           `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+func (m *Message) Envelope() Envelope {
+	return Envelope{
+		ID:          m.EventID,
+		Event:       m.EventName,
+		AggregateID: m.AggregateID,
+		OccurredAt:  m.OccurredAt,
+		Payload:     json.RawMessage(m.Payload),
+	}
+}
+
+// SinkNames имена приемников, хранится в jsonb колонке
+type SinkNames []string
+
+func (n SinkNames) Value() (driver.Value, error) {
+	if n == nil {
+		n = SinkNames{}
+	}
+	return json.Marshal([]string(n))
+}
+
+func (n *SinkNames) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*n = SinkNames{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, (*[]string)(n))
+	case string:
+		return json.Unmarshal([]byte(data), (*[]string)(n))
+	}
+	return fmt.Errorf("unsupported sink names value type %T", value)
+}
+
+func (n SinkNames) Contains(name string) bool {
+	return slices.Contains(n, name)
+}
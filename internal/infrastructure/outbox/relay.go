@@ -0,0 +1,207 @@
+package outbox
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/infrastructure/lib/log"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pendingSQL выбирает первое недоставленное сообщение каждого агрегата. Пока оно не доставлено
+// и не переведено в dead letter, следующие сообщения агрегата не выбираются, что сохраняет порядок.
+// Забранные сообщения не выбираются до истечения аренды, так как next_attempt_at сдвинут на ее конец.
+// SKIP LOCKED позволяет нескольким экземплярам сервиса забирать пачки одновременно
+const pendingSQL = `SELECT * FROM outbox_messages m
+WHERE m.delivered_at IS NULL AND m.dead_lettered_at IS NULL AND m.next_attempt_at <= ?
+AND NOT EXISTS (SELECT 1 FROM outbox_messages p WHERE p.aggregate_id = m.aggregate_id AND p.id < m.id
+	AND p.delivered_at IS NULL AND p.dead_lettered_at IS NULL)
+ORDER BY m.id LIMIT ? FOR UPDATE SKIP LOCKED`
+
+// Relay доставляет сообщения outbox во все приемники. Сообщение считается доставленным,
+// только когда его приняли все приемники, иначе повторяется с экспоненциальной задержкой
+// для приемников, которые его еще не приняли
+type Relay struct {
+	cfg   config.OutboxConfig
+	log   *slog.Logger
+	db    *gorm.DB
+	sinks []Sink
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRelay(cfg config.OutboxConfig, log *slog.Logger, db *gorm.DB, sinks ...Sink) *Relay {
+	return &Relay{
+		cfg:   cfg,
+		log:   log,
+		db:    db,
+		sinks: sinks,
+	}
+}
+
+// Start запускает цикл доставки в отдельной горутине
+func (r *Relay) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+}
+
+// Stop останавливает доставку и дожидается завершения текущей пачки
+func (r *Relay) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+func (r *Relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	r.log.Info("outbox relay started", slog.Int("sinks", len(r.sinks)))
+	for {
+		r.drain(ctx)
+		select {
+		case <-ctx.Done():
+			r.log.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain обрабатывает пачки, пока они выбираются полностью
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := r.ProcessBatch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.Error("outbox: failed to process batch", log.Err(err))
+			}
+			return
+		}
+		if processed < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// ProcessBatch доставляет одну пачку сообщений и возвращает их количество. Пачка забирается
+// в короткой транзакции, доставка идет вне транзакции, чтобы сетевые вызовы не держали
+// блокировки и соединение пула. Результат записывается по каждому сообщению отдельно
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	messages, leasedUntil, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range messages {
+		message := &messages[i]
+		r.deliver(ctx, message)
+		if err := r.record(ctx, message, leasedUntil); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// claim забирает пачку сообщений: сдвигает их next_attempt_at на конец аренды, после чего
+// другие экземпляры не выберут их, пока аренда не истечет
+func (r *Relay) claim(ctx context.Context) ([]Message, time.Time, error) {
+	now := time.Now().UTC()
+	// postgres хранит время с точностью до микросекунд, иначе сравнение в record не совпадет
+	leasedUntil := now.Add(r.cfg.ClaimTimeout).Truncate(time.Microsecond)
+	var messages []Message
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(pendingSQL, now, max(r.cfg.BatchSize, 1)).Scan(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		ids := make([]int64, 0, len(messages))
+		for i := range messages {
+			messages[i].NextAttemptAt = leasedUntil
+			ids = append(ids, messages[i].ID)
+		}
+		return tx.Model(&Message{}).Where("id IN ?", ids).Update("next_attempt_at", leasedUntil).Error
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return messages, leasedUntil, nil
+}
+
+// record сохраняет результат доставки, если аренда еще принадлежит этому экземпляру.
+// После истечения аренды сообщение мог забрать другой экземпляр, и результат пропускается.
+// Результат уже выполненной доставки сохраняется и при остановке relay
+func (r *Relay) record(ctx context.Context, message *Message, leasedUntil time.Time) error {
+	result := r.db.WithContext(context.WithoutCancel(ctx)).Model(message).Where("next_attempt_at = ?", leasedUntil).
+		Select("attempts", "next_attempt_at", "last_error", "delivered_at", "dead_lettered_at", "delivered_sinks").
+		Updates(message)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		r.log.Warn("outbox: claim expired before the result was recorded", slog.Int64("id", message.ID),
+			slog.String("event", message.EventName))
+	}
+	return nil
+}
+
+// deliver отправляет сообщение в приемники, еще не принявшие его, и обновляет его состояние.
+// Если доставка прервана остановкой relay, попытка не засчитывается и сообщение сразу
+// становится доступным для повторной выборки
+func (r *Relay) deliver(ctx context.Context, message *Message) {
+	envelope := message.Envelope()
+	var errs []string
+	for _, sink := range r.sinks {
+		if message.DeliveredSinks.Contains(sink.Name()) {
+			continue
+		}
+		if err := sink.Deliver(ctx, envelope); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", sink.Name(), err))
+			continue
+		}
+		message.DeliveredSinks = append(message.DeliveredSinks, sink.Name())
+	}
+
+	now := time.Now().UTC()
+	if len(errs) > 0 && ctx.Err() != nil {
+		message.NextAttemptAt = now
+		return
+	}
+	message.Attempts++
+	if len(errs) == 0 {
+		message.DeliveredAt = &now
+		message.LastError = ""
+		return
+	}
+	message.LastError = strings.Join(errs, "; ")
+	attrs := []any{slog.Int64("id", message.ID), slog.String("event", message.EventName),
+		slog.Int64("aggregate_id", message.AggregateID), slog.Int("attempts", message.Attempts),
+		log.Err(errors.New(message.LastError))}
+	if message.Attempts >= r.cfg.MaxAttempts {
+		message.DeadLetteredAt = &now
+		r.log.Error("outbox: message moved to dead letter", attrs...)
+		return
+	}
+	message.NextAttemptAt = now.Add(r.backoff(message.Attempts))
+	r.log.Warn("outbox: delivery failed, retrying", attrs...)
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.cfg.RetryBackoff
+	for i := 1; i < attempts && backoff < r.cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, r.cfg.MaxBackoff)
+}
@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"challenge-service/config"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stubSink считает доставки и возвращает err
+type stubSink struct {
+	name       string
+	err        error
+	deliveries int
+}
+
+func (s *stubSink) Name() string {
+	return s.name
+}
+
+func (s *stubSink) Deliver(ctx context.Context, _ Envelope) error {
+	s.deliveries++
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.err
+}
+
+func TestRelayDeliver(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		failing        error
+		delivered      SinkNames
+		attempts       int
+		wantAttempts   int
+		wantDelivered  bool
+		wantDeadLetter bool
+		wantSinks      SinkNames
+		wantCalls      [2]int
+	}{
+		{name: "all sinks accept", ctx: context.Background(), wantAttempts: 1, wantDelivered: true,
+			wantSinks: SinkNames{"log", "notifications"}, wantCalls: [2]int{1, 1}},
+		{name: "failed sink is retried alone", ctx: context.Background(), failing: errors.New("unavailable"),
+			wantAttempts: 1, wantSinks: SinkNames{"notifications"}, wantCalls: [2]int{1, 1}},
+		{name: "sink that accepted earlier is skipped", ctx: context.Background(), delivered: SinkNames{"notifications"},
+			attempts: 1, wantAttempts: 2, wantDelivered: true, wantSinks: SinkNames{"notifications", "log"},
+			wantCalls: [2]int{1, 0}},
+		{name: "last attempt moves to dead letter", ctx: context.Background(), failing: errors.New("unavailable"),
+			attempts: 2, wantAttempts: 3, wantDeadLetter: true, wantSinks: SinkNames{"notifications"},
+			wantCalls: [2]int{1, 1}},
+		{name: "cancelled delivery is not counted", ctx: cancelled, attempts: 1, wantAttempts: 1,
+			wantCalls: [2]int{1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sinks := []*stubSink{{name: "log", err: tt.failing}, {name: "notifications"}}
+			relay := NewRelay(config.OutboxConfig{MaxAttempts: 3, RetryBackoff: time.Second, MaxBackoff: time.Minute},
+				slog.New(slog.NewTextHandler(io.Discard, nil)), nil, sinks[0], sinks[1])
+			leasedUntil := time.Now().Add(time.Hour)
+			message := &Message{Payload: "{}", Attempts: tt.attempts, NextAttemptAt: leasedUntil,
+				DeliveredSinks: slices.Clone(tt.delivered)}
+
+			relay.deliver(tt.ctx, message)
+
+			if message.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", message.Attempts, tt.wantAttempts)
+			}
+			if (message.DeliveredAt != nil) != tt.wantDelivered || (message.DeadLetteredAt != nil) != tt.wantDeadLetter {
+				t.Errorf("DeliveredAt = %v, DeadLetteredAt = %v", message.DeliveredAt, message.DeadLetteredAt)
+			}
+			if !slices.Equal(message.DeliveredSinks, tt.wantSinks) {
+				t.Errorf("DeliveredSinks = %q, want %q", message.DeliveredSinks, tt.wantSinks)
+			}
+			if calls := [2]int{sinks[0].deliveries, sinks[1].deliveries}; calls != tt.wantCalls {
+				t.Errorf("deliveries = %v, want %v", calls, tt.wantCalls)
+			}
+			if !tt.wantDelivered && !tt.wantDeadLetter && !message.NextAttemptAt.Before(leasedUntil) {
+				t.Error("message is still leased, want it scheduled for another attempt")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"bytes"
+	"challenge-service/config"
+	"challenge-service/internal/infrastructure/tracing"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+)
+
+// Sink приемник событий. Deliver должен быть идемпотентным по Envelope.ID:
+// при сбоях сообщение может быть доставлено повторно
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, envelope Envelope) error
+}
+
+func NewSinks(cfg config.OutboxConfig, log *slog.Logger) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(cfg.Sinks))
+	for _, name := range cfg.Sinks {
+		switch strings.TrimSpace(name) {
+		case SinkLog:
+			sinks = append(sinks, NewLogSink(log))
+		case SinkWebhook:
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("outbox webhook sink requires webhookURL")
+			}
+			sinks = append(sinks, NewWebhookSink(cfg.WebhookURL, cfg.WebhookSecret,
+				&http.Client{Timeout: cfg.WebhookTimeout, Transport: tracing.Transport(nil)}))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+// LogSink пишет события в лог, используется при разработке
+type LogSink struct {
+	log *slog.Logger
+}
+
+func NewLogSink(log *slog.Logger) *LogSink {
+	return &LogSink{log: log}
+}
+
+func (s *LogSink) Name() string {
+	return SinkLog
+}
+
+func (s *LogSink) Deliver(ctx context.Context, envelope Envelope) error {
+	s.log.Info("outbox event", slog.String("id", envelope.ID), slog.String("event", envelope.Event),
+		slog.Int64("aggregate_id", envelope.AggregateID), slog.String("payload", string(envelope.Payload)))
+	return nil
+}
+
+// WebhookSink отправляет события POST запросом. Если задан secret, тело подписывается
+// HMAC-SHA256 в заголовке X-Outbox-Signature
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func NewWebhookSink(url string, secret string, client *http.Client) *WebhookSink {
+	return &WebhookSink{url: url, secret: []byte(secret), client: client}
+}
+
+func (s *WebhookSink) Name() string {
+	return SinkWebhook
+}
+
+func (s *WebhookSink) Deliver(ctx context.Context, envelope Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Outbox-Event", envelope.Event)
+	req.Header.Set("X-Outbox-Event-Id", envelope.ID)
+	if len(s.secret) > 0 {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(body)
+		req.Header.Set("X-Outbox-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
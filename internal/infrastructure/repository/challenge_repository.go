@@ -4,12 +4,42 @@ import (
 	"challenge-service/config"
 	"challenge-service/internal/domain/challenge/entity"
 	interfaceRepo "challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
 	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/outbox"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log/slog"
 	"math/rand/v2"
+	"slices"
+	"time"
 )
 
+const (
+	participantCountSQL  = "(SELECT count(*) FROM authentication_participant WHERE authentication_participant.challenge_id = authentication_challenge.id)"
+	participantExistsSQL = "EXISTS (SELECT 1 FROM authentication_participant WHERE authentication_participant.challenge_id = authentication_challenge.id"
+
+	// uniqueViolationCode код ошибки Postgres при нарушении уникального индекса
+	uniqueViolationCode = "23505"
+)
+
+// participantUniqueIndexes индексы миграции 0009, запрещающие повторную регистрацию
+var participantUniqueIndexes = []string{
+	"idx_authentication_participant_challenge_user",
+	"idx_authentication_participant_challenge_team",
+}
+
+var challengeSortColumns = map[string]string{
+	interfaceRepo.SortByStartDate:    "authentication_challenge.start_date",
+	interfaceRepo.SortByEndDate:      "authentication_challenge.end_date",
+	interfaceRepo.SortByName:         "authentication_challenge.name",
+	interfaceRepo.SortByParticipants: participantCountSQL,
+}
+
 type challengeRepository struct {
 	interfaceRepo.ChallengeRepositoryInterface
 	cfg *config.Config
@@ -25,128 +55,494 @@ func NewChallengeRepository(cfg *config.Config, log *slog.Logger, db *gorm.DB) i
 	}
 }
 
+// conn транзакция единицы работы из контекста или собственное соединение репозитория
+func (c *challengeRepository) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return c.db.WithContext(ctx)
+}
+
+// logger логгер запроса из контекста, для фоновых задач - логгер репозитория
+func (c *challengeRepository) logger(ctx context.Context) *slog.Logger {
+	return log.FromContext(ctx, c.log)
+}
+
 // Получение всех вызовов
-func (c *challengeRepository) FindAll() ([]*entity.AuthenticationChallenge, error) {
+func (c *challengeRepository) FindAll(ctx context.Context) ([]*entity.AuthenticationChallenge, error) {
 	var challenges []*entity.AuthenticationChallenge
-	if err := c.db.Find(&challenges).Error; err != nil {
-		c.log.Error("failed to fetch challenges", log.Err(err))
+	if err := c.conn(ctx).Find(&challenges).Error; err != nil {
+		c.logger(ctx).Error("failed to fetch challenges", log.Err(err))
 		return nil, err
 	}
 	return challenges, nil
 }
 
+// Получение вызова по ID
+func (c *challengeRepository) FindByID(ctx context.Context,
+	challengeID int64) (*entity.AuthenticationChallenge, error) {
+	var challenge entity.AuthenticationChallenge
+	if err := c.conn(ctx).First(&challenge, challengeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrChallengeNotFound
+		}
+		c.logger(ctx).Error("failed to fetch challenge", log.Err(err))
+		return nil, err
+	}
+	return &challenge, nil
+}
+
 // Создание нового вызова
-func (c *challengeRepository) Create(challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
-	if err := c.db.Create(&challenge).Error; err != nil {
-		c.log.Error("failed to create challenge", log.Err(err))
+func (c *challengeRepository) Create(ctx context.Context,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
+	if err := c.conn(ctx).Create(&challenge).Error; err != nil {
+		c.logger(ctx).Error("failed to create challenge", log.Err(err))
 		return nil, err
 	}
 	return &challenge, nil
 }
 
-// Обновление существующего вызова
-func (c *challengeRepository) Update(challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
-	if err := c.db.Save(&challenge).Error; err != nil {
-		c.log.Error("failed to update challenge", log.Err(err))
+// Частичное обновление вызова: записываются только поля fields и увеличенная версия
+func (c *challengeRepository) Update(ctx context.Context, challenge entity.AuthenticationChallenge,
+	fields ...string) (*entity.AuthenticationChallenge, error) {
+	if len(fields) == 0 {
+		return &challenge, nil
+	}
+	if err := c.updateVersioned(c.conn(ctx), &challenge, fields); err != nil {
+		if !errors.Is(err, entity.ErrStaleVersion) {
+			c.logger(ctx).Error("failed to update challenge", log.Err(err))
+		}
 		return nil, err
 	}
 	return &challenge, nil
 }
 
-// Удаление вызова по ID
-func (c *challengeRepository) Delete(challengeID int64) error {
-	if err := c.db.Delete(&entity.AuthenticationChallenge{}, challengeID).Error; err != nil {
-		c.log.Error("failed to delete challenge", log.Err(err))
+// updateVersioned обновляет поля fields, если версия вызова в базе не изменилась с момента чтения.
+// Иначе вызов изменил другой запрос и возвращается entity.ErrStaleVersion
+func (c *challengeRepository) updateVersioned(db *gorm.DB, challenge *entity.AuthenticationChallenge,
+	fields []string) error {
+	expected := challenge.Version
+	challenge.Version++
+	result := db.Model(challenge).Where("version = ?", expected).
+		Select(append([]string{"Version"}, fields...)).Updates(challenge)
+	if result.Error != nil {
+		challenge.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		challenge.Version = expected
+		return entity.ErrStaleVersion
+	}
+	return nil
+}
+
+// Перемещение вызова в корзину по ID
+func (c *challengeRepository) Delete(ctx context.Context, challengeID int64) error {
+	result := c.conn(ctx).Delete(&entity.AuthenticationChallenge{}, challengeID)
+	if err := result.Error; err != nil {
+		c.logger(ctx).Error("failed to delete challenge", log.Err(err))
 		return err
 	}
+	if result.RowsAffected == 0 {
+		return entity.ErrChallengeNotFound
+	}
 	return nil
 }
 
-// Поиск вызовов по параметрам
-func (c *challengeRepository) FindByParams(params *interfaceRepo.AuthenticationChallengeParams) ([]*entity.AuthenticationChallenge, error) {
+// Вызовы создателя в корзине, последние удаленные первыми
+func (c *challengeRepository) FindDeleted(ctx context.Context,
+	creatorID int64) ([]*entity.AuthenticationChallenge, error) {
 	var challenges []*entity.AuthenticationChallenge
-	query := c.db.Model(&entity.AuthenticationChallenge{})
-
-	if *params.Name != "" {
-		query = query.Where("name = ?", params.Name)
-	}
-	if *params.Type != "" {
-		query = query.Where("type = ?", params.Type)
+	if err := c.conn(ctx).Unscoped().Where("creator_id = ? AND deleted_at IS NOT NULL", creatorID).
+		Order("deleted_at DESC").Order("id").Find(&challenges).Error; err != nil {
+		c.logger(ctx).Error("failed to fetch deleted challenges", log.Err(err))
+		return nil, err
 	}
-	if params.IsTeam != nil {
-		query = query.Where("end_date = ?", params.IsTeam)
+	return challenges, nil
+}
+
+// Получение вызова из корзины по ID
+func (c *challengeRepository) FindDeletedByID(ctx context.Context,
+	challengeID int64) (*entity.AuthenticationChallenge, error) {
+	var challenge entity.AuthenticationChallenge
+	if err := c.conn(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&challenge, challengeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrChallengeNotFound
+		}
+		c.logger(ctx).Error("failed to fetch deleted challenge", log.Err(err))
+		return nil, err
 	}
+	return &challenge, nil
+}
 
-	if err := query.Find(&challenges).Error; err != nil {
-		c.log.Error("failed to find challenges by params", log.Err(err))
+// Восстановление вызова из корзины, версия увеличивается как при любом изменении
+func (c *challengeRepository) Restore(ctx context.Context,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
+	challenge.DeletedAt = gorm.DeletedAt{}
+	if err := c.updateVersioned(c.conn(ctx).Unscoped(), &challenge, []string{"DeletedAt"}); err != nil {
+		if !errors.Is(err, entity.ErrStaleVersion) {
+			c.logger(ctx).Error("failed to restore challenge", log.Err(err))
+		}
 		return nil, err
 	}
-	return challenges, nil
+	return &challenge, nil
 }
 
-// Получение всех вызовов пользователя по userID
-func (c *challengeRepository) GetAllChallengesFromUser(userID string) ([]*entity.AuthenticationChallenge, error) {
-	var challenges []*entity.AuthenticationChallenge
-	if err := c.db.Joins("JOIN authentication_participants ON authentication_participants.challenge_id = authentication_challenges.id").
-		Where("authentication_participants.user_id = ?", userID).
-		Find(&challenges).Error; err != nil {
-		c.log.Error("failed to fetch user challenges", log.Err(err))
+// Окончательное удаление вызовов, которые лежат в корзине дольше срока хранения.
+// Участники удаляются каскадно внешним ключом, удаленные строки возвращаются через RETURNING
+func (c *challengeRepository) PurgeDeleted(ctx context.Context, before time.Time,
+	limit int) ([]*entity.AuthenticationChallenge, error) {
+	expired := c.conn(ctx).Unscoped().Model(&entity.AuthenticationChallenge{}).Select("id").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Order("deleted_at").Limit(limit)
+	var purged []*entity.AuthenticationChallenge
+	if err := c.conn(ctx).Unscoped().Clauses(clause.Returning{}).Where("id IN (?)", expired).
+		Delete(&purged).Error; err != nil {
+		c.logger(ctx).Error("failed to purge deleted challenges", log.Err(err))
 		return nil, err
 	}
-	return challenges, nil
+	return purged, nil
 }
 
-// Получение всех вызовов для команды по teamID
-func (c *challengeRepository) GetAllChallengesFromTeam(teamID string) ([]*entity.AuthenticationChallenge, error) {
+// Поиск вызовов по параметрам с сортировкой и пагинацией по курсору
+func (c *challengeRepository) FindByParams(ctx context.Context,
+	params *interfaceRepo.AuthenticationChallengeParams) (*interfaceRepo.ChallengePage, error) {
+	sortBy := params.SortBy
+	if sortBy == "" {
+		sortBy = interfaceRepo.SortByStartDate
+	}
+	if err := interfaceRepo.ValidSortKey(sortBy); err != nil {
+		return nil, err
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = interfaceRepo.DefaultPageSize
+	}
+	limit = min(limit, interfaceRepo.MaxPageSize)
+
+	query := c.conn(ctx).Model(&entity.AuthenticationChallenge{}).
+		Select("authentication_challenge.*, " + participantCountSQL + " AS participant_count")
+	if params.Name != nil && *params.Name != "" {
+		query = query.Where("authentication_challenge.name = ?", *params.Name)
+	}
+	if params.Type != nil && *params.Type != "" {
+		query = query.Where("authentication_challenge.type = ?", *params.Type)
+	}
+	if params.IsTeam != nil {
+		query = query.Where("authentication_challenge.is_team = ?", *params.IsTeam)
+	}
+	if params.IsFinished != nil {
+		query = query.Where("authentication_challenge.is_finished = ?", *params.IsFinished)
+	}
+	if params.CreatorID != nil {
+		query = query.Where("authentication_challenge.creator_id = ?", *params.CreatorID)
+	}
+	if params.ParticipantUserID != nil {
+		query = query.Where(participantExistsSQL+" AND authentication_participant.user_id = ?)", *params.ParticipantUserID)
+	}
+	if params.ParticipantTeamID != nil {
+		query = query.Where(participantExistsSQL+" AND authentication_participant.team_id = ?)", *params.ParticipantTeamID)
+	}
+	if params.StartFrom != nil {
+		query = query.Where("authentication_challenge.start_date >= ?", *params.StartFrom)
+	}
+	if params.StartTo != nil {
+		query = query.Where("authentication_challenge.start_date <= ?", *params.StartTo)
+	}
+	if params.EndFrom != nil {
+		query = query.Where("authentication_challenge.end_date >= ?", *params.EndFrom)
+	}
+	if params.EndTo != nil {
+		query = query.Where("authentication_challenge.end_date <= ?", *params.EndTo)
+	}
+
+	column := challengeSortColumns[sortBy]
+	direction, comparison := "ASC", ">"
+	if params.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+	if cursor := params.Cursor; cursor != nil {
+		if cursor.SortBy != sortBy || cursor.Desc != params.SortDesc {
+			return nil, fmt.Errorf("%w: cursor was issued for another sort order", interfaceRepo.ErrInvalidCursor)
+		}
+		value, err := parseSortValue(sortBy, cursor.Value)
+		if err != nil {
+			return nil, interfaceRepo.ErrInvalidCursor
+		}
+		query = query.Where(fmt.Sprintf("(%s, authentication_challenge.id) %s (?, ?)", column, comparison), value, cursor.ID)
+	}
+
 	var challenges []*entity.AuthenticationChallenge
-	if err := c.db.Joins("JOIN authentication_participants ON authentication_participants.challenge_id = authentication_challenges.id").
-		Where("authentication_participants.team_id = ?", teamID).
-		Find(&challenges).Error; err != nil {
-		c.log.Error("failed to fetch team challenges", log.Err(err))
+	if err := query.Order(column + " " + direction).Order("authentication_challenge.id " + direction).
+		Limit(limit + 1).Find(&challenges).Error; err != nil {
+		c.logger(ctx).Error("failed to find challenges by params", log.Err(err))
 		return nil, err
 	}
-	return challenges, nil
+
+	page := &interfaceRepo.ChallengePage{Items: challenges}
+	if len(challenges) > limit {
+		page.Items = challenges[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = interfaceRepo.ChallengeCursor{
+			SortBy: sortBy,
+			Desc:   params.SortDesc,
+			Value:  formatSortValue(sortBy, last),
+			ID:     last.ID,
+		}.Encode()
+	}
+	return page, nil
 }
 
-func (c *challengeRepository) RegisterUserOnChallenge(userID int64,
+func (c *challengeRepository) RegisterUserOnChallenge(ctx context.Context, userID int64,
 	challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error) {
 	var par entity.AuthenticationParticipant
 	par = entity.AuthenticationParticipant{
 		ID:          rand.Int64(),
-		Status:      "In Progress",
+		Status:      entity.ParticipantStatusActive,
 		Achievement: challenge.Name,
+		Progress:    entity.NewParticipantProgress(challenge),
 		ChallengeID: challenge.ID,
 		UserID:      userID,
 	}
-	if err := c.db.Create(&par).Error; err != nil {
-		c.log.Error("failed to create challenge response", log.Err(err))
+	if err := c.createParticipant(ctx, &par); err != nil {
 		return nil, err
 	}
 	return &par, nil
 }
 
-func (c *challengeRepository) RegisterTeamOnChallenge(teamID int64,
+func (c *challengeRepository) RegisterTeamOnChallenge(ctx context.Context, teamID int64,
 	challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error) {
 	var par entity.AuthenticationParticipant
 	par = entity.AuthenticationParticipant{
 		ID:          rand.Int64(),
-		Status:      "In Progress",
+		Status:      entity.ParticipantStatusActive,
 		Achievement: challenge.Name,
+		Progress:    entity.NewParticipantProgress(challenge),
 		ChallengeID: challenge.ID,
 		TeamID:      teamID,
 	}
-	if err := c.db.Create(&par).Error; err != nil {
-		c.log.Error("failed to create challenge response", log.Err(err))
+	if err := c.createParticipant(ctx, &par); err != nil {
 		return nil, err
 	}
 	return &par, nil
 }
 
-func (c *challengeRepository) CloseChallenge(challengeID int64) (*entity.AuthenticationChallenge, error) {
-	var challenge entity.AuthenticationChallenge
-	if err := c.db.Model(&challenge).Where("challenge_id = ?", challengeID).Update("is_finished", true).Error; err != nil {
-		c.log.Error("failed to close challenge", log.Err(err))
+// createParticipant сохраняет участника. Параллельная повторная регистрация отклоняется
+// уникальными индексами участников и возвращается как entity.ErrAlreadyRegistered
+func (c *challengeRepository) createParticipant(ctx context.Context, par *entity.AuthenticationParticipant) error {
+	if err := c.conn(ctx).Create(par).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode &&
+			slices.Contains(participantUniqueIndexes, pgErr.ConstraintName) {
+			return entity.ErrAlreadyRegistered
+		}
+		c.logger(ctx).Error("failed to create challenge response", log.Err(err))
+		return err
+	}
+	return nil
+}
+
+// Сохранение состояния жизненного цикла вызова
+func (c *challengeRepository) UpdateStatus(ctx context.Context,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
+	if err := c.updateVersioned(c.conn(ctx), &challenge, []string{"Status", "IsFinished"}); err != nil {
+		if !errors.Is(err, entity.ErrStaleVersion) {
+			c.logger(ctx).Error("failed to update challenge status", log.Err(err))
+		}
 		return nil, err
 	}
 	return &challenge, nil
 }
+
+// Получение вызовов в заданном состоянии жизненного цикла
+func (c *challengeRepository) FindByStatus(ctx context.Context,
+	status entity.ChallengeStatus) ([]*entity.AuthenticationChallenge, error) {
+	var challenges []*entity.AuthenticationChallenge
+	if err := c.conn(ctx).Where("status = ?", status).Find(&challenges).Error; err != nil {
+		c.logger(ctx).Error("failed to fetch challenges by status", log.Err(err))
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// Получение всех участников вызова
+func (c *challengeRepository) GetParticipants(ctx context.Context,
+	challengeID int64) ([]*entity.AuthenticationParticipant, error) {
+	var participants []*entity.AuthenticationParticipant
+	if err := c.conn(ctx).Where("challenge_id = ?", challengeID).Find(&participants).Error; err != nil {
+		c.logger(ctx).Error("failed to fetch participants", log.Err(err))
+		return nil, err
+	}
+	return participants, nil
+}
+
+// leaderboardSQL ранжирует субъектов вызова (%[1]s - user_id или team_id) по правилам entity.Leaderboard.
+// Прогресс участников одного субъекта суммируется, total - число субъектов для пагинации
+const leaderboardSQL = `WITH subjects AS (
+	SELECT %[1]s AS subject_id,
+		COALESCE(sum((progress->>'total')::float8), 0) AS score,
+		COALESCE(max(NULLIF(progress->>'unit', '')), '') AS unit,
+		COALESCE(max((progress->>'days_done')::int), 0) AS days_done,
+		max((progress->>'last_check_in')::timestamptz) AS last_check_in
+	FROM authentication_participant WHERE challenge_id = ? GROUP BY %[1]s
+), ranked AS (
+	SELECT *, row_number() OVER (ORDER BY score DESC, days_done DESC, last_check_in ASC NULLS LAST, subject_id) AS rank,
+		count(*) OVER () AS total
+	FROM subjects
+)
+SELECT * FROM ranked WHERE (rank > ? AND rank <= ?) OR subject_id = NULLIF(?::bigint, 0) ORDER BY rank`
+
+type leaderboardRow struct {
+	SubjectID   int64
+	Score       float64
+	Unit        string
+	DaysDone    int
+	LastCheckIn *time.Time
+	Rank        int
+	Total       int
+}
+
+// Ранжирование участников вызова в базе
+func (c *challengeRepository) RankParticipants(ctx context.Context, challenge entity.AuthenticationChallenge,
+	offset int, limit int, me int64) ([]entity.LeaderboardEntry, int, error) {
+	subject := "user_id"
+	if challenge.IsTeam {
+		subject = "team_id"
+	}
+	var rows []leaderboardRow
+	if err := c.conn(ctx).Raw(fmt.Sprintf(leaderboardSQL, subject), challenge.ID, offset, offset+limit, me).
+		Scan(&rows).Error; err != nil {
+		c.logger(ctx).Error("failed to rank participants", log.Err(err))
+		return nil, 0, err
+	}
+	total := 0
+	entries := make([]entity.LeaderboardEntry, 0, len(rows))
+	for _, row := range rows {
+		total = row.Total
+		entry := entity.LeaderboardEntry{
+			Rank:        row.Rank,
+			Score:       row.Score,
+			Unit:        row.Unit,
+			DaysDone:    row.DaysDone,
+			LastCheckIn: row.LastCheckIn,
+		}
+		if challenge.IsTeam {
+			entry.TeamID = row.SubjectID
+		} else {
+			entry.UserID = row.SubjectID
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, nil
+}
+
+// Поиск участника вызова: по команде, если teamID задан, иначе по пользователю
+func (c *challengeRepository) FindParticipant(ctx context.Context, challengeID int64, userID int64,
+	teamID int64) (*entity.AuthenticationParticipant, error) {
+	return c.findParticipant(c.conn(ctx), ctx, challengeID, userID, teamID)
+}
+
+// Поиск участника с блокировкой строки до конца транзакции (SELECT ... FOR UPDATE)
+func (c *challengeRepository) LockParticipant(ctx context.Context, challengeID int64, userID int64,
+	teamID int64) (*entity.AuthenticationParticipant, error) {
+	return c.findParticipant(c.conn(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), ctx, challengeID, userID, teamID)
+}
+
+func (c *challengeRepository) findParticipant(db *gorm.DB, ctx context.Context, challengeID int64, userID int64,
+	teamID int64) (*entity.AuthenticationParticipant, error) {
+	var par entity.AuthenticationParticipant
+	query := db.Where("challenge_id = ?", challengeID)
+	if teamID != 0 {
+		query = query.Where("team_id = ?", teamID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.First(&par).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrParticipantNotFound
+		}
+		c.logger(ctx).Error("failed to fetch participant", log.Err(err))
+		return nil, err
+	}
+	return &par, nil
+}
+
+func (c *challengeRepository) UpdateParticipantStatus(ctx context.Context, participantID int64, status string) error {
+	if err := c.conn(ctx).Model(&entity.AuthenticationParticipant{}).Where("id = ?", participantID).
+		Update("status", status).Error; err != nil {
+		c.logger(ctx).Error("failed to update participant status", log.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (c *challengeRepository) UpdateParticipantProgress(ctx context.Context, participantID int64,
+	progress entity.ParticipantProgress) (*entity.AuthenticationParticipant, error) {
+	var par entity.AuthenticationParticipant
+	if err := c.conn(ctx).Model(&entity.AuthenticationParticipant{}).Where("id = ?", participantID).
+		Update("progress", progress).Error; err != nil {
+		c.logger(ctx).Error("failed to update participant progress", log.Err(err))
+		return nil, err
+	}
+	if err := c.conn(ctx).First(&par, participantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrParticipantNotFound
+		}
+		c.logger(ctx).Error("failed to fetch participant", log.Err(err))
+		return nil, err
+	}
+	return &par, nil
+}
+
+// challengeReminder отправленное напоминание о днях до конца вызова
+type challengeReminder struct {
+	ChallengeID int64     `gorm:"primaryKey"`
+	DaysLeft    int       `gorm:"primaryKey"`
+	SentAt      time.Time `gorm:"type:timestamptz;not null"`
+}
+
+func (challengeReminder) TableName() string {
+	return "challenge_reminders"
+}
+
+// Отметка напоминания: вставка проходит только у первого экземпляра, остальные получают конфликт ключа
+func (c *challengeRepository) ClaimReminder(ctx context.Context, challengeID int64, daysLeft int) (bool, error) {
+	result := c.conn(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&challengeReminder{
+		ChallengeID: challengeID,
+		DaysLeft:    daysLeft,
+		SentAt:      time.Now().UTC(),
+	})
+	if err := result.Error; err != nil {
+		c.logger(ctx).Error("failed to claim reminder", log.Err(err))
+		return false, err
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Выполнение fn в транзакции: изменения вызова и события outbox фиксируются вместе
+func (c *challengeRepository) Transaction(ctx context.Context,
+	fn func(repo interfaceRepo.ChallengeRepositoryInterface) error) error {
+	return c.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&challengeRepository{cfg: c.cfg, log: c.log, db: tx})
+	})
+}
+
+// Сохранение событий в outbox
+func (c *challengeRepository) AppendEvents(ctx context.Context, events ...cqrs.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	messages := make([]*outbox.Message, 0, len(events))
+	for _, event := range events {
+		message, err := outbox.NewMessage(event)
+		if err != nil {
+			c.logger(ctx).Error("failed to encode event", slog.String("event", event.EventName()), log.Err(err))
+			return err
+		}
+		messages = append(messages, message)
+	}
+	if err := c.conn(ctx).Create(&messages).Error; err != nil {
+		c.logger(ctx).Error("failed to append events to outbox", log.Err(err))
+		return err
+	}
+	return nil
+}
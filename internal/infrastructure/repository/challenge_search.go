@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	interfaceRepo "challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/lib/log"
+	"context"
+	"html"
+	"strings"
+)
+
+const (
+	// challengeSearchVector документ для поиска: название весомее описания, текст разбирается
+	// русской и английской конфигурациями. GIN индекс по этому выражению создается миграцией 0003
+	challengeSearchVector = "(setweight(to_tsvector('russian', authentication_challenge.name), 'A') || " +
+		"setweight(to_tsvector('english', authentication_challenge.name), 'A') || " +
+		"setweight(to_tsvector('russian', authentication_challenge.description), 'B') || " +
+		"setweight(to_tsvector('english', authentication_challenge.description), 'B'))"
+	challengeSearchQuery = "(websearch_to_tsquery('russian', ?) || websearch_to_tsquery('english', ?))"
+
+	// границы совпадений во фрагментах заменяются на <mark> после экранирования текста
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var (
+	nameHeadline        = headline("authentication_challenge.name", "StartSel=\x02, StopSel=\x03, HighlightAll=true")
+	descriptionHeadline = headline("authentication_challenge.description",
+		"StartSel=\x02, StopSel=\x03, MaxFragments=2, MaxWords=25, MinWords=8, FragmentDelimiter=\" … \"")
+)
+
+// headline фрагмент column с выделенными совпадениями. Фрагмент строится той конфигурацией,
+// по которой поле совпало с запросом: русской, иначе английской, чтобы формы слов выделялись
+// с правильным стеммингом. Принимает запрос трижды
+func headline(column string, options string) string {
+	return "CASE WHEN to_tsvector('russian', " + column + ") @@ websearch_to_tsquery('russian', ?) " +
+		"THEN ts_headline('russian', " + column + ", websearch_to_tsquery('russian', ?), '" + options + "') " +
+		"ELSE ts_headline('english', " + column + ", websearch_to_tsquery('english', ?), '" + options + "') END"
+}
+
+type challengeSearchRow struct {
+	entity.AuthenticationChallenge
+	SearchRank           float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
+
+// Полнотекстовый поиск вызовов, результаты упорядочены по релевантности
+func (c *challengeRepository) Search(ctx context.Context,
+	params *interfaceRepo.ChallengeSearchParams) (*interfaceRepo.ChallengeSearchPage, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = interfaceRepo.DefaultPageSize
+	}
+	limit = min(limit, interfaceRepo.MaxPageSize)
+	q := params.Query
+
+	query := c.conn(ctx).Model(&entity.AuthenticationChallenge{}).
+		Select("authentication_challenge.*, "+
+			"ts_rank("+challengeSearchVector+", "+challengeSearchQuery+") AS search_rank, "+
+			nameHeadline+" AS name_highlight, "+
+			descriptionHeadline+" AS description_highlight", q, q, q, q, q, q, q, q).
+		Where(challengeSearchVector+" @@ "+challengeSearchQuery, q, q)
+	if params.Type != nil && *params.Type != "" {
+		query = query.Where("authentication_challenge.type = ?", *params.Type)
+	}
+	if params.IsTeam != nil {
+		query = query.Where("authentication_challenge.is_team = ?", *params.IsTeam)
+	}
+
+	var rows []*challengeSearchRow
+	if err := query.Order("search_rank DESC").Order("authentication_challenge.id").
+		Limit(limit).Offset(max(params.Offset, 0)).Find(&rows).Error; err != nil {
+		c.logger(ctx).Error("failed to search challenges", log.Err(err))
+		return nil, err
+	}
+
+	page := &interfaceRepo.ChallengeSearchPage{Items: make([]*interfaceRepo.ChallengeSearchResult, 0, len(rows))}
+	for _, row := range rows {
+		challenge := row.AuthenticationChallenge
+		page.Items = append(page.Items, &interfaceRepo.ChallengeSearchResult{
+			Challenge:            &challenge,
+			Rank:                 row.SearchRank,
+			NameHighlight:        highlight(row.NameHighlight),
+			DescriptionHighlight: highlight(row.DescriptionHighlight),
+		})
+	}
+	return page, nil
+}
+
+// highlight экранирует фрагмент и размечает совпадения тегом <mark>
+func highlight(fragment string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(fragment))
+}
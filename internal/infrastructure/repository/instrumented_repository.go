@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	interfaceRepo "challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"time"
+)
+
+// RepositoryObserver получает длительность и результат каждого вызова метода репозитория
+type RepositoryObserver interface {
+	ObserveRepository(method string, duration time.Duration, err error)
+}
+
+// instrumentedRepository измеряет время выполнения методов репозитория
+type instrumentedRepository struct {
+	repo     interfaceRepo.ChallengeRepositoryInterface
+	observer RepositoryObserver
+}
+
+func NewInstrumentedRepository(repo interfaceRepo.ChallengeRepositoryInterface,
+	observer RepositoryObserver) interfaceRepo.ChallengeRepositoryInterface {
+	return &instrumentedRepository{repo: repo, observer: observer}
+}
+
+func (r *instrumentedRepository) observe(method string, started time.Time, err error) {
+	r.observer.ObserveRepository(method, time.Since(started), err)
+}
+
+func (r *instrumentedRepository) Create(ctx context.Context,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.Create(ctx, challenge)
+	r.observe("Create", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) Delete(ctx context.Context, challengeID int64) error {
+	started := time.Now()
+	err := r.repo.Delete(ctx, challengeID)
+	r.observe("Delete", started, err)
+	return err
+}
+
+func (r *instrumentedRepository) Update(ctx context.Context, challenge entity.AuthenticationChallenge,
+	fields ...string) (*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.Update(ctx, challenge, fields...)
+	r.observe("Update", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) FindByID(ctx context.Context,
+	challengeID int64) (*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.FindByID(ctx, challengeID)
+	r.observe("FindByID", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) FindAll(ctx context.Context) ([]*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.FindAll(ctx)
+	r.observe("FindAll", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) FindByParams(ctx context.Context,
+	params *interfaceRepo.AuthenticationChallengeParams) (*interfaceRepo.ChallengePage, error) {
+	started := time.Now()
+	result, err := r.repo.FindByParams(ctx, params)
+	r.observe("FindByParams", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) Search(ctx context.Context,
+	params *interfaceRepo.ChallengeSearchParams) (*interfaceRepo.ChallengeSearchPage, error) {
+	started := time.Now()
+	result, err := r.repo.Search(ctx, params)
+	r.observe("Search", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) FindDeleted(ctx context.Context,
+	creatorID int64) ([]*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.FindDeleted(ctx, creatorID)
+	r.observe("FindDeleted", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) FindDeletedByID(ctx context.Context,
+	challengeID int64) (*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.FindDeletedByID(ctx, challengeID)
+	r.observe("FindDeletedByID", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) Restore(ctx context.Context,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.Restore(ctx, challenge)
+	r.observe("Restore", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) PurgeDeleted(ctx context.Context, before time.Time,
+	limit int) ([]*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.PurgeDeleted(ctx, before, limit)
+	r.observe("PurgeDeleted", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) RegisterUserOnChallenge(ctx context.Context, userID int64,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error) {
+	started := time.Now()
+	result, err := r.repo.RegisterUserOnChallenge(ctx, userID, challenge)
+	r.observe("RegisterUserOnChallenge", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) RegisterTeamOnChallenge(ctx context.Context, teamID int64,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationParticipant, error) {
+	started := time.Now()
+	result, err := r.repo.RegisterTeamOnChallenge(ctx, teamID, challenge)
+	r.observe("RegisterTeamOnChallenge", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) UpdateStatus(ctx context.Context,
+	challenge entity.AuthenticationChallenge) (*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.UpdateStatus(ctx, challenge)
+	r.observe("UpdateStatus", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) FindByStatus(ctx context.Context,
+	status entity.ChallengeStatus) ([]*entity.AuthenticationChallenge, error) {
+	started := time.Now()
+	result, err := r.repo.FindByStatus(ctx, status)
+	r.observe("FindByStatus", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) GetParticipants(ctx context.Context,
+	challengeID int64) ([]*entity.AuthenticationParticipant, error) {
+	started := time.Now()
+	result, err := r.repo.GetParticipants(ctx, challengeID)
+	r.observe("GetParticipants", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) RankParticipants(ctx context.Context, challenge entity.AuthenticationChallenge,
+	offset int, limit int, me int64) ([]entity.LeaderboardEntry, int, error) {
+	started := time.Now()
+	entries, total, err := r.repo.RankParticipants(ctx, challenge, offset, limit, me)
+	r.observe("RankParticipants", started, err)
+	return entries, total, err
+}
+
+func (r *instrumentedRepository) FindParticipant(ctx context.Context, challengeID int64, userID int64,
+	teamID int64) (*entity.AuthenticationParticipant, error) {
+	started := time.Now()
+	result, err := r.repo.FindParticipant(ctx, challengeID, userID, teamID)
+	r.observe("FindParticipant", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) LockParticipant(ctx context.Context, challengeID int64, userID int64,
+	teamID int64) (*entity.AuthenticationParticipant, error) {
+	started := time.Now()
+	result, err := r.repo.LockParticipant(ctx, challengeID, userID, teamID)
+	r.observe("LockParticipant", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) UpdateParticipantStatus(ctx context.Context,
+	participantID int64, status string) error {
+	started := time.Now()
+	err := r.repo.UpdateParticipantStatus(ctx, participantID, status)
+	r.observe("UpdateParticipantStatus", started, err)
+	return err
+}
+
+func (r *instrumentedRepository) UpdateParticipantProgress(ctx context.Context, participantID int64,
+	progress entity.ParticipantProgress) (*entity.AuthenticationParticipant, error) {
+	started := time.Now()
+	result, err := r.repo.UpdateParticipantProgress(ctx, participantID, progress)
+	r.observe("UpdateParticipantProgress", started, err)
+	return result, err
+}
+
+func (r *instrumentedRepository) ClaimReminder(ctx context.Context, challengeID int64, daysLeft int) (bool, error) {
+	started := time.Now()
+	result, err := r.repo.ClaimReminder(ctx, challengeID, daysLeft)
+	r.observe("ClaimReminder", started, err)
+	return result, err
+}
+
+// Transaction измеряет транзакцию целиком, а методы внутри нее - по отдельности
+func (r *instrumentedRepository) Transaction(ctx context.Context,
+	fn func(repo interfaceRepo.ChallengeRepositoryInterface) error) error {
+	started := time.Now()
+	err := r.repo.Transaction(ctx, func(repo interfaceRepo.ChallengeRepositoryInterface) error {
+		return fn(&instrumentedRepository{repo: repo, observer: r.observer})
+	})
+	r.observe("Transaction", started, err)
+	return err
+}
+
+func (r *instrumentedRepository) AppendEvents(ctx context.Context, events ...cqrs.Event) error {
+	started := time.Now()
+	err := r.repo.AppendEvents(ctx, events...)
+	r.observe("AppendEvents", started, err)
+	return err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"challenge-service/internal/domain/challenge/entity"
+	interfaceRepo "challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"strconv"
+	"time"
+)
+
+// formatSortValue значение ключа сортировки вызова для курсора
+func formatSortValue(sortBy string, challenge *entity.AuthenticationChallenge) string {
+	switch sortBy {
+	case interfaceRepo.SortByEndDate:
+		return challenge.EndDate.UTC().Format(time.RFC3339Nano)
+	case interfaceRepo.SortByName:
+		return challenge.Name
+	case interfaceRepo.SortByParticipants:
+		return strconv.FormatInt(challenge.ParticipantCount, 10)
+	}
+	return challenge.StartDate.UTC().Format(time.RFC3339Nano)
+}
+
+func parseSortValue(sortBy string, value string) (interface{}, error) {
+	switch sortBy {
+	case interfaceRepo.SortByName:
+		return value, nil
+	case interfaceRepo.SortByParticipants:
+		return strconv.ParseInt(value, 10, 64)
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
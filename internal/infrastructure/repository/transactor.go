@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor единица работы для шины команд: открывает транзакцию и передает ее через контекст,
+// репозитории выполняют запросы в ней
+type Transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// Within выполняет fn в транзакции, если в контексте ее еще нет, иначе присоединяется к открытой
+func (t *Transactor) Within(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/commands"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"challenge-service/internal/infrastructure/lib/auth"
+	"challenge-service/internal/infrastructure/lib/log"
+	"context"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ChallengeScheduler периодически запускает запланированные вызовы и закрывает вызовы,
+// у которых прошла дата окончания. Переходы выполняются через обработчики команд.
+// Также рассылает участникам напоминания о том, сколько дней осталось до конца вызова.
+type ChallengeScheduler struct {
+	cfg      *config.Config
+	log      *slog.Logger
+	bus      *cqrs.Bus
+	repo     repository_interface.ChallengeRepositoryInterface
+	notifier notifier_interface.NotifierInterface
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewChallengeScheduler(cfg *config.Config, log *slog.Logger, bus *cqrs.Bus,
+	repo repository_interface.ChallengeRepositoryInterface,
+	notifier notifier_interface.NotifierInterface) *ChallengeScheduler {
+	return &ChallengeScheduler{
+		cfg:      cfg,
+		log:      log,
+		bus:      bus,
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+// Start запускает цикл планировщика в отдельной горутине
+func (s *ChallengeScheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(auth.WithPrincipal(ctx, auth.SystemPrincipal()))
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx)
+	}()
+}
+
+// Stop останавливает планировщик и дожидается завершения текущего тика
+func (s *ChallengeScheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *ChallengeScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SchedulerInterval)
+	defer ticker.Stop()
+
+	s.log.Info("challenge scheduler started", slog.Duration("interval", s.cfg.SchedulerInterval))
+	s.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("challenge scheduler stopped")
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *ChallengeScheduler) tick(ctx context.Context) {
+	now := time.Now()
+	s.startDue(ctx, now)
+	s.finishDue(ctx, now)
+}
+
+func (s *ChallengeScheduler) startDue(ctx context.Context, now time.Time) {
+	challenges, err := s.repo.FindByStatus(ctx, entity.ChallengeStatusScheduled)
+	if err != nil {
+		s.log.Error("scheduler: failed to fetch scheduled challenges", log.Err(err))
+		return
+	}
+	for _, challenge := range challenges {
+		if ctx.Err() != nil {
+			return
+		}
+		if !challenge.DueToStart(now) {
+			continue
+		}
+		transition(ctx, s, commands.NewStartChallengeCommand(rand.Int64(), challenge.ID), challenge.ID)
+	}
+}
+
+func (s *ChallengeScheduler) finishDue(ctx context.Context, now time.Time) {
+	challenges, err := s.repo.FindByStatus(ctx, entity.ChallengeStatusActive)
+	if err != nil {
+		s.log.Error("scheduler: failed to fetch active challenges", log.Err(err))
+		return
+	}
+	for _, challenge := range challenges {
+		if ctx.Err() != nil {
+			return
+		}
+		if !challenge.DueToFinish(now) {
+			s.remindDaysLeft(ctx, challenge, now)
+			continue
+		}
+		transition(ctx, s, commands.NewCloseChallengeCommand(rand.Int64(), challenge.ID), challenge.ID)
+	}
+}
+
+// remindDaysLeft отправляет напоминание, когда до конца вызова остается одно из значений notifications.daysLeft.
+// Отметка об отправке и событие напоминания записываются в outbox одной транзакцией, поэтому напоминание
+// уходит один раз при любом числе экземпляров и не теряется при сбое после отметки
+func (s *ChallengeScheduler) remindDaysLeft(ctx context.Context, challenge *entity.AuthenticationChallenge, now time.Time) {
+	daysLeft := int(math.Ceil(challenge.EndDate.Sub(now).Hours() / 24))
+	if !slices.Contains(s.cfg.Notifications.DaysLeft, daysLeft) {
+		return
+	}
+
+	participants, err := s.repo.GetParticipants(ctx, challenge.ID)
+	if err != nil {
+		s.log.Error("scheduler: failed to fetch participants", slog.Int64("challenge_id", challenge.ID), log.Err(err))
+		return
+	}
+	reminder := events.NewDaysLeftReminder(challenge, participants, daysLeft)
+	claimed := false
+	err = s.repo.Transaction(ctx, func(repo repository_interface.ChallengeRepositoryInterface) error {
+		var err error
+		if claimed, err = repo.ClaimReminder(ctx, challenge.ID, daysLeft); err != nil || !claimed {
+			return err
+		}
+		return repo.AppendEvents(ctx, reminder)
+	})
+	if err != nil {
+		s.log.Error("scheduler: failed to record reminder", slog.Int64("challenge_id", challenge.ID), log.Err(err))
+		return
+	}
+	// без outbox событие не будет доставлено, поэтому напоминание ставится в очередь рассылки здесь
+	if claimed && !s.cfg.Outbox.Enabled {
+		for _, notification := range events.Notifications(reminder) {
+			s.notifier.Notify(ctx, notification)
+		}
+	}
+}
+
+// transition выполняет команду перехода жизненного цикла вызова
+func transition[C cqrs.Command](ctx context.Context, s *ChallengeScheduler, command C, challengeID int64) {
+	if _, err := cqrs.Dispatch[C, *entity.AuthenticationChallenge](ctx, s.bus, command); err != nil {
+		s.log.Error("scheduler: failed to transition challenge", slog.Int64("challenge_id", challengeID), log.Err(err))
+		return
+	}
+	s.log.Info("scheduler: challenge transitioned", slog.Int64("challenge_id", challengeID))
+}
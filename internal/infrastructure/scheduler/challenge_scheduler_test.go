@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/events"
+	"challenge-service/internal/domain/challenge/usecases/notifier_interface"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/cqrs"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// reminderRepository отмечает напоминания в памяти и запоминает события, записанные в outbox
+// вместе с отметкой. При ошибке appendErr транзакция откатывается вместе с отметкой
+type reminderRepository struct {
+	repository_interface.ChallengeRepositoryInterface
+	claimed   map[int]bool
+	appendErr error
+	appended  []cqrs.Event
+}
+
+func (r *reminderRepository) GetParticipants(context.Context, int64) ([]*entity.AuthenticationParticipant, error) {
+	return []*entity.AuthenticationParticipant{{UserID: 42}, {TeamID: 7}}, nil
+}
+
+func (r *reminderRepository) Transaction(_ context.Context,
+	fn func(repo repository_interface.ChallengeRepositoryInterface) error) error {
+	tx := &reminderRepository{claimed: map[int]bool{}, appendErr: r.appendErr}
+	for daysLeft := range r.claimed {
+		tx.claimed[daysLeft] = true
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.claimed, r.appended = tx.claimed, append(r.appended, tx.appended...)
+	return nil
+}
+
+func (r *reminderRepository) ClaimReminder(_ context.Context, _ int64, daysLeft int) (bool, error) {
+	if r.claimed[daysLeft] {
+		return false, nil
+	}
+	r.claimed[daysLeft] = true
+	return true, nil
+}
+
+func (r *reminderRepository) AppendEvents(_ context.Context, events ...cqrs.Event) error {
+	if r.appendErr != nil {
+		return r.appendErr
+	}
+	r.appended = append(r.appended, events...)
+	return nil
+}
+
+type countingNotifier struct {
+	notifications []notifier_interface.Notification
+}
+
+func (n *countingNotifier) Notify(_ context.Context, notification notifier_interface.Notification) {
+	n.notifications = append(n.notifications, notification)
+}
+
+func TestRemindDaysLeft(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	challenge := &entity.AuthenticationChallenge{ID: 1, EndDate: now.Add(72 * time.Hour)}
+
+	tests := []struct {
+		name          string
+		endDate       time.Time
+		outbox        bool
+		claimed       bool
+		appendErr     error
+		wantAppended  int
+		wantNotified  int
+		wantClaimKept bool
+	}{
+		{name: "reminder is written to the outbox", outbox: true, wantAppended: 1, wantClaimKept: true},
+		{name: "reminder sent by another instance", outbox: true, claimed: true, wantClaimKept: true},
+		{name: "failed outbox write keeps the reminder unclaimed", outbox: true, appendErr: errors.New("db down")},
+		{name: "not a reminder day", outbox: true, endDate: now.Add(48 * time.Hour)},
+		{name: "without outbox the reminder is queued directly", wantAppended: 1, wantNotified: 1, wantClaimKept: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &reminderRepository{claimed: map[int]bool{}, appendErr: tt.appendErr}
+			if tt.claimed {
+				repo.claimed[3] = true
+			}
+			notifier := &countingNotifier{}
+			cfg := &config.Config{
+				Notifications: config.NotificationsConfig{DaysLeft: []int{3, 1}},
+				Outbox:        config.OutboxConfig{Enabled: tt.outbox},
+			}
+			scheduler := NewChallengeScheduler(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil, repo, notifier)
+			reminded := *challenge
+			if !tt.endDate.IsZero() {
+				reminded.EndDate = tt.endDate
+			}
+
+			scheduler.remindDaysLeft(context.Background(), &reminded, now)
+
+			if len(repo.appended) != tt.wantAppended {
+				t.Fatalf("appended %d events, want %d", len(repo.appended), tt.wantAppended)
+			}
+			if tt.wantAppended > 0 {
+				reminder, ok := repo.appended[0].(*events.DaysLeftReminder)
+				if !ok || reminder.DaysLeft != 3 || len(reminder.Participants) != 2 {
+					t.Fatalf("appended %+v, want days left reminder for 2 participants", repo.appended[0])
+				}
+			}
+			if len(notifier.notifications) != tt.wantNotified {
+				t.Fatalf("notified %d times, want %d", len(notifier.notifications), tt.wantNotified)
+			}
+			if repo.claimed[3] != tt.wantClaimKept {
+				t.Fatalf("claimed = %v, want %v", repo.claimed[3], tt.wantClaimKept)
+			}
+		})
+	}
+}
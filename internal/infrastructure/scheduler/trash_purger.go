@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/lib/log"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// TrashPurger периодически окончательно удаляет вызовы, пролежавшие в корзине дольше trash.retention.
+// Удаление идет пачками по trash.purgeBatchSize, чтобы не держать долгие блокировки.
+// Изображения удаленных вызовов удаляются из хранилища
+type TrashPurger struct {
+	cfg     *config.Config
+	log     *slog.Logger
+	repo    repository_interface.ChallengeRepositoryInterface
+	storage storage.Storage
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTrashPurger(cfg *config.Config, log *slog.Logger,
+	repo repository_interface.ChallengeRepositoryInterface, storage storage.Storage) *TrashPurger {
+	return &TrashPurger{
+		cfg:     cfg,
+		log:     log,
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+// Start запускает очистку корзины в отдельной горутине
+func (p *TrashPurger) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.run(ctx)
+	}()
+}
+
+// Stop останавливает очистку и дожидается завершения текущего прохода
+func (p *TrashPurger) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+func (p *TrashPurger) run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Trash.PurgeInterval)
+	defer ticker.Stop()
+
+	p.log.Info("trash purger started", slog.Duration("interval", p.cfg.Trash.PurgeInterval),
+		slog.Duration("retention", p.cfg.Trash.Retention))
+	p.purge(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			p.log.Info("trash purger stopped")
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+// purge удаляет пачки, пока очередная пачка заполнена целиком
+func (p *TrashPurger) purge(ctx context.Context) {
+	before := time.Now().Add(-p.cfg.Trash.Retention)
+	batchSize := max(p.cfg.Trash.PurgeBatchSize, 1)
+	var total int64
+	for ctx.Err() == nil {
+		purged, err := p.repo.PurgeDeleted(ctx, before, batchSize)
+		if err != nil {
+			p.log.Error("failed to purge trash", log.Err(err))
+			break
+		}
+		p.deleteImages(ctx, purged)
+		total += int64(len(purged))
+		if len(purged) < batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		p.log.Info("trash purged", slog.Int64("challenges", total))
+	}
+}
+
+// deleteImages удаляет из хранилища изображения окончательно удаленных вызовов. Вызовы уже удалены,
+// поэтому ошибка удаления файла только логируется и не прерывает очистку
+func (p *TrashPurger) deleteImages(ctx context.Context, challenges []*entity.AuthenticationChallenge) {
+	ctx = context.WithoutCancel(ctx)
+	for _, challenge := range challenges {
+		for _, url := range challenge.ImageURLs() {
+			key, ok := p.storage.KeyFromURL(url)
+			if !ok {
+				continue
+			}
+			if err := p.storage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotSupported) {
+				p.log.Warn("failed to delete image of purged challenge", slog.Int64("challenge_id", challenge.ID),
+					slog.String("key", key), log.Err(err))
+			}
+		}
+	}
+}
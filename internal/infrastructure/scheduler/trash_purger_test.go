@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"challenge-service/config"
+	"challenge-service/internal/domain/challenge/entity"
+	"challenge-service/internal/domain/challenge/usecases/repository_interface"
+	"challenge-service/internal/infrastructure/lib/storage"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// purgeRepository отдает заранее заданные пачки удаленных вызовов
+type purgeRepository struct {
+	repository_interface.ChallengeRepositoryInterface
+	batches [][]*entity.AuthenticationChallenge
+}
+
+func (r *purgeRepository) PurgeDeleted(context.Context, time.Time, int) ([]*entity.AuthenticationChallenge, error) {
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+// recordingStorage запоминает удаленные ключи, удаление ключей из failing завершается ошибкой
+type recordingStorage struct {
+	storage.Storage
+	failing []string
+	deleted []string
+}
+
+func (s *recordingStorage) KeyFromURL(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://cdn/")
+}
+
+func (s *recordingStorage) Delete(_ context.Context, key string) error {
+	if slices.Contains(s.failing, key) {
+		return errors.New("storage unavailable")
+	}
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func TestTrashPurgerDeletesImages(t *testing.T) {
+	repo := &purgeRepository{batches: [][]*entity.AuthenticationChallenge{
+		{
+			{ID: 1, Icon: "https://cdn/a.png", Image: "https://cdn/a.jpg",
+				IconVariants: entity.ImageVariants{"64": "https://cdn/a_64.png"}},
+			{ID: 2, Icon: "https://cdn/b.png", ImageVariants: entity.ImageVariants{"card": "https://cdn/b_card.jpg"}},
+		},
+		{{ID: 3, Icon: "https://legacy/c.png", Image: "https://cdn/c.jpg"}},
+	}}
+	store := &recordingStorage{failing: []string{"a.png"}}
+	purger := NewTrashPurger(&config.Config{Trash: config.TrashConfig{PurgeBatchSize: 2}},
+		slog.New(slog.NewTextHandler(io.Discard, nil)), repo, store)
+
+	purger.purge(context.Background())
+
+	slices.Sort(store.deleted)
+	// ошибка удаления a.png и чужой URL иконки c.png не прерывают очистку
+	want := []string{"a.jpg", "a_64.png", "b.png", "b_card.jpg", "c.jpg"}
+	if !slices.Equal(store.deleted, want) {
+		t.Fatalf("deleted = %q, want %q", store.deleted, want)
+	}
+	if len(repo.batches) != 0 {
+		t.Fatalf("%d batches left, want all purged", len(repo.batches))
+	}
+}
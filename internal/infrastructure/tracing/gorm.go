@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin создает спан на каждый SQL запрос gorm. Родительский спан берется из контекста,
+// переданного через db.WithContext, поэтому запросы попадают в трассу HTTP запроса
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("tracing:before_create", startSpan("create")),
+		callback.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		callback.Query().Before("gorm:query").Register("tracing:before_query", startSpan("query")),
+		callback.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		callback.Update().Before("gorm:update").Register("tracing:before_update", startSpan("update")),
+		callback.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		callback.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("delete")),
+		callback.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		callback.Row().Before("gorm:row").Register("tracing:before_row", startSpan("row")),
+		callback.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		callback.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("raw")),
+		callback.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		name := "gorm." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		ctx, span := Tracer().Start(db.Statement.Context, name, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation)))
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}